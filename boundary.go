@@ -0,0 +1,79 @@
+package gomock
+
+import (
+	"context"
+)
+
+type boundaryKey struct{}
+
+// boundary select the boundary value of the generators, index < 0 select one randomly
+type boundary struct {
+	index int
+	max   int //the max number of the boundary values of the fields, only for Boundaries
+}
+
+// WithBoundary mock the boundary values derived from the tags in this call instead of the uniformly random values:
+// integer and decimal take min, min+1, max-1, max or zero, string and slice take the length min, min+1, max-1, max,
+// options take the first or the last option. eq, reg and the other mock functions are not changed.
+// the values outside the range are given by Invalid.
+func WithBoundary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, boundaryKey{}, &boundary{index: -1})
+}
+
+// Boundaries mock the values of T one by one, the value i takes the boundary value i of every field,
+// so all the boundary values of every field are enumerated by the fewest values. T is a struct or a struct ptr
+func Boundaries[T any](ctx context.Context, m *Mock) ([]T, error) {
+	var (
+		values []T
+		b      = &boundary{}
+	)
+	ctx = context.WithValue(ctx, boundaryKey{}, b)
+	for ; b.index == 0 || b.index < b.max; b.index++ {
+		value, err := makeOne[T](ctx, m)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+func getBoundary(ctx context.Context) (*boundary, bool) {
+	b, ok := ctx.Value(boundaryKey{}).(*boundary)
+	return b, ok
+}
+
+// pickBoundary select one of the distinct candidates
+func pickBoundary[T comparable](ctx context.Context, b *boundary, candidates []T) T {
+	var (
+		distinct = make([]T, 0, len(candidates))
+		seen     = make(map[T]struct{}, len(candidates))
+	)
+	for _, candidate := range candidates {
+		if _, ok := seen[candidate]; ok {
+			continue
+		}
+		seen[candidate] = struct{}{}
+		distinct = append(distinct, candidate)
+	}
+	if b.index < 0 { //WithBoundary, b is shared by the concurrent calls and read only
+		return distinct[GetRand(ctx).Intn(len(distinct))]
+	}
+	b.max = maxFunc(b.max, len(distinct))
+	return distinct[b.index%len(distinct)]
+}
+
+// rangeBoundaries return the boundary values of [gte,lt), include zero if it is in range
+func rangeBoundaries(gte, lt int64) []int64 {
+	candidates := []int64{gte, gte + 1, lt - 2, lt - 1}
+	if gte < 0 && lt > 0 {
+		candidates = append(candidates, 0)
+	}
+	values := candidates[:0]
+	for _, candidate := range candidates {
+		if candidate >= gte && candidate < lt {
+			values = append(values, candidate)
+		}
+	}
+	return values
+}
@@ -0,0 +1,313 @@
+package gomock
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// RuleBuilder build the mock tags of a struct by code, every rule is checked against the field type,
+// the rules of a field are merged into its registered tag config on the next Field, Err or Must,
+// the first error is kept and returned by Err.
+//
+//	err := m.For(&User{}).Field("Age").Int(18, 65).Field("Tags").Len(1, 5).Each().Options("a", "b").Err()
+type RuleBuilder struct {
+	m      *Mock
+	root   reflect.Type //the struct
+	owner  reflect.Type //the struct of the current field
+	name   string       //the name of the current field
+	rt     reflect.Type //the current type, the element type after Each
+	tags   []string     //the tags of the current field, not registered yet
+	rule   [][]string   //the registered tags of the current field separated by into
+	depth  int          //the number of Each
+	hasKey bool         //the tags after the last into have key
+	err    error
+}
+
+// For start to build the rules of the struct
+func (m *Mock) For(s any) *RuleBuilder {
+	rb := &RuleBuilder{m: m}
+	rt := reflect.TypeOf(s)
+	if rt != nil {
+		rt, _ = m.Indirect(rt)
+	}
+	if rt == nil || rt.Kind() != reflect.Struct {
+		rb.err = errors.New("not a struct or struct ptr")
+		return rb
+	}
+	rb.root = rt
+	return rb
+}
+
+// Field select the field of the struct, the nested field is separated by dot, eg: Hobby.Name
+func (rb *RuleBuilder) Field(path string) *RuleBuilder {
+	if rb.flush(); rb.err != nil {
+		return rb
+	}
+	rb.owner, rb.rt, rb.tags, rb.depth = rb.root, rb.root, nil, 0
+	names := strings.Split(path, tagConfigSeparator)
+	for i, name := range names {
+		if rb.rt.Kind() != reflect.Struct {
+			return rb.fail(fmt.Errorf("field:%s,err:%s is not a struct", path, strings.Join(names[:i], ".")))
+		}
+		rs, ok := rb.rt.FieldByName(name)
+		if !ok || len(rs.Index) > 1 || !rs.IsExported() {
+			return rb.fail(fmt.Errorf("field:%s,err:not found the field %s", path, name))
+		}
+		if i > 0 { //the parent must be into
+			rb.m.mergeTagRule(typeKey(rb.owner)+tagConfigSeparator+rb.name, MockInto+mockTagSeparator+"1", TagConfigMerge)
+		}
+		rb.owner, rb.name = rb.rt, name
+		rb.rt, _ = rb.m.Indirect(rs.Type)
+	}
+	if _, ok := notSupportTypes[rb.rt.Kind()]; ok {
+		return rb.fail(fmt.Errorf("field:%s,err:not support the kind:%s", path, rb.rt.Kind()))
+	}
+	tag, _ := rb.m.TagConfig(rb.key())
+	rb.rule = rb.m.segmentTag(rb.m.splitTag(tag))
+	rb.hasKey = rb.ruleHasKey()
+	return rb
+}
+
+// Each turn to the elements of the slice
+func (rb *RuleBuilder) Each() *RuleBuilder {
+	if !rb.check(rb.rt != nil && rb.rt.Kind() == reflect.Slice, "only support the type slice") {
+		return rb
+	}
+	rb.rt, _ = rb.m.Indirect(rb.rt.Elem())
+	rb.depth++
+	rb.hasKey = rb.ruleHasKey()
+	return rb.add(MockInto, "1")
+}
+
+// Key appoint the mock function
+func (rb *RuleBuilder) Key(key string) *RuleBuilder {
+	if !rb.check(rb.rt != nil, "not select any field") {
+		return rb
+	}
+	rb.m.Lock()
+	_, ok := rb.m.mockFactory[key]
+	rb.m.Unlock()
+	if !rb.check(ok, "not found mock key type:"+key) {
+		return rb
+	}
+	rb.hasKey = true
+	return rb.add(MockKey, key)
+}
+
+// Int mock integer in [min,max]
+func (rb *RuleBuilder) Int(min, max int64) *RuleBuilder {
+	if !rb.check(isIntegerKind(rb.rt), "only support the type integer") || !rb.checkRange(min > max) {
+		return rb
+	}
+	return rb.withKey().add(MockGte, strconv.FormatInt(min, 10)).add(MockLte, strconv.FormatInt(max, 10))
+}
+
+// Decimal mock decimal in [min,max]
+func (rb *RuleBuilder) Decimal(min, max float64) *RuleBuilder {
+	if !rb.check(isDecimalKind(rb.rt), "only support the type decimal") || !rb.checkRange(min > max) {
+		return rb
+	}
+	return rb.withKey().add(MockGte, strconv.FormatFloat(min, 'f', -1, 64)).
+		add(MockLte, strconv.FormatFloat(max, 'f', -1, 64))
+}
+
+// Len limit the length of string or slice in [min,max]
+func (rb *RuleBuilder) Len(min, max int) *RuleBuilder {
+	if !rb.check(rb.rt != nil && (rb.rt.Kind() == reflect.String || rb.rt.Kind() == reflect.Slice),
+		"only support the type string or slice") || !rb.checkRange(min > max) {
+		return rb
+	}
+	return rb.withKey().add(MockGte, strconv.Itoa(min)).add(MockLte, strconv.Itoa(max))
+}
+
+// Eq ensure the value is equal to the given, for slice, it ensures the length
+func (rb *RuleBuilder) Eq(value any) *RuleBuilder {
+	if rb.rt != nil && rb.rt.Kind() == reflect.Slice {
+		_, ok := value.(int)
+		if !rb.check(ok, "the length of slice must be int") {
+			return rb
+		}
+		return rb.add(MockEqual, fmt.Sprint(value))
+	}
+	if !rb.checkValue(value) {
+		return rb
+	}
+	return rb.withKey().add(MockEqual, fmt.Sprint(value))
+}
+
+// Options specify optional data
+func (rb *RuleBuilder) Options(values ...any) *RuleBuilder {
+	options := make([]string, 0, len(values))
+	for _, value := range values {
+		if !rb.checkValue(value) {
+			return rb
+		}
+		option := fmt.Sprint(value)
+		if !rb.check(option != "" && !strings.Contains(option, mockTagValSeparator), "invalid option:"+option) {
+			return rb
+		}
+		options = append(options, option)
+	}
+	if !rb.check(len(options) > 0, "empty options") {
+		return rb
+	}
+	return rb.withKey().add(MockOptions, strings.Join(options, mockTagValSeparator))
+}
+
+// Weights specify the weight of the options
+func (rb *RuleBuilder) Weights(weights ...int64) *RuleBuilder {
+	values := make([]string, 0, len(weights))
+	for _, weight := range weights {
+		values = append(values, strconv.FormatInt(weight, 10))
+	}
+	return rb.add(MockWeights, strings.Join(values, mockTagValSeparator))
+}
+
+// Reg generate content based on regular expression
+func (rb *RuleBuilder) Reg(pattern string) *RuleBuilder {
+	if !rb.check(rb.rt != nil && (rb.rt.Kind() == reflect.String || isIntegerKind(rb.rt) || isDecimalKind(rb.rt)),
+		"only support the type string, integer or decimal") {
+		return rb
+	}
+	return rb.withKey().add(MockRegExp, pattern)
+}
+
+// Skip skip the field
+func (rb *RuleBuilder) Skip() *RuleBuilder {
+	return rb.add(MockSkip, "1")
+}
+
+// Tag add any registered tag, eg: Tag("time", "ts_ms")
+func (rb *RuleBuilder) Tag(key, value string) *RuleBuilder {
+	rb.m.Lock()
+	_, ok := rb.m.tagFactory[key]
+	rb.m.Unlock()
+	if !rb.check(ok, "not support the mock tag:"+key) {
+		return rb
+	}
+	if key == MockKey {
+		rb.hasKey = true
+	}
+	return rb.add(key, value)
+}
+
+// Err register the rules of the current field and return the first error of the rules
+func (rb *RuleBuilder) Err() error {
+	rb.flush()
+	return rb.err
+}
+
+// Must register the rules of the current field and panic if any rule is invalid
+func (rb *RuleBuilder) Must() *RuleBuilder {
+	if rb.flush(); rb.err != nil {
+		panic(rb.err)
+	}
+	return rb
+}
+
+func (rb *RuleBuilder) withKey() *RuleBuilder {
+	if rb.err != nil || rb.hasKey {
+		return rb
+	}
+	switch {
+	case rb.rt.Kind() == reflect.String:
+		return rb.Key(makeString)
+	case isIntegerKind(rb.rt):
+		return rb.Key(makeInteger)
+	case isDecimalKind(rb.rt):
+		return rb.Key(makeDecimal)
+	}
+	return rb
+}
+
+// add the tag to the current field
+func (rb *RuleBuilder) add(key, value string) *RuleBuilder {
+	if !rb.check(rb.rt != nil, "not select any field") {
+		return rb
+	}
+	rb.tags = append(rb.tags, key+mockTagSeparator+value)
+	return rb
+}
+
+// flush merge the tags of the current field into its registered rule once, the cache is invalidated once
+func (rb *RuleBuilder) flush() {
+	if rb.err == nil && len(rb.tags) > 0 {
+		rb.m.mergeTagRule(rb.key(), strings.Join(rb.tags, defaultSeparator), TagConfigOverride)
+	}
+	rb.tags = nil
+}
+
+func (rb *RuleBuilder) key() string {
+	return typeKey(rb.owner) + tagConfigSeparator + rb.name
+}
+
+// ruleHasKey report whether the registered rule of the current depth has key
+func (rb *RuleBuilder) ruleHasKey() bool {
+	if rb.depth >= len(rb.rule) {
+		return false
+	}
+	for _, tag := range rb.rule[rb.depth] {
+		if rb.m.tagKey(tag) == MockKey {
+			return true
+		}
+	}
+	return false
+}
+
+func (rb *RuleBuilder) checkValue(value any) bool {
+	if !rb.check(rb.rt != nil, "not select any field") {
+		return false
+	}
+	vt := reflect.TypeOf(value)
+	switch {
+	case vt == nil:
+		return rb.check(false, "nil value")
+	case rb.rt.Kind() == reflect.String:
+		return rb.check(vt.Kind() == reflect.String, fmt.Sprintf("%v is not a string", value))
+	case isIntegerKind(rb.rt):
+		return rb.check(isIntegerKind(vt), fmt.Sprintf("%v is not a integer", value))
+	case isDecimalKind(rb.rt):
+		return rb.check(isIntegerKind(vt) || isDecimalKind(vt), fmt.Sprintf("%v is not a decimal", value))
+	}
+	return rb.check(false, "only support the type string, integer or decimal")
+}
+
+func (rb *RuleBuilder) checkRange(reversed bool) bool {
+	return rb.check(!reversed, "min is greater than max")
+}
+
+func (rb *RuleBuilder) check(ok bool, msg string) bool {
+	if rb.err != nil {
+		return false
+	}
+	if !ok {
+		rb.fail(fmt.Errorf("field:%s.%s,err:%s", rb.root, rb.name, msg))
+	}
+	return ok
+}
+
+func (rb *RuleBuilder) fail(err error) *RuleBuilder {
+	if rb.err == nil {
+		rb.err = err
+	}
+	return rb
+}
+
+func isIntegerKind(rt reflect.Type) bool {
+	if rt == nil {
+		return false
+	}
+	switch rt.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+func isDecimalKind(rt reflect.Type) bool {
+	return rt != nil && (rt.Kind() == reflect.Float32 || rt.Kind() == reflect.Float64)
+}
@@ -7,11 +7,13 @@ import (
 	"regexp"
 	"strings"
 	"sync"
+	"sync/atomic"
 )
 
 type cache struct {
 	lock  *sync.Mutex
-	cache *sync.Map // map[reflect.Type]*structParse
+	cache *sync.Map     // map[reflect.Type]*structParse
+	epoch atomic.Uint64 //increased once the cached trees are deleted, see GenCache
 }
 
 var (
@@ -61,6 +63,24 @@ func (c *cache) get(rt reflect.Type) *mockField {
 	}
 	return value.(*mockField)
 }
+
+// invalidate delete the cached trees matched by fn
+func (c *cache) invalidate(fn func(mf *mockField) bool) {
+	c.cache.Range(func(key, value any) bool {
+		if fn(value.(*mockField)) {
+			c.cache.Delete(key)
+			c.epoch.Add(1)
+		}
+		return true
+	})
+}
+func (c *cache) reset() {
+	c.cache.Range(func(key, _ any) bool {
+		c.cache.Delete(key)
+		return true
+	})
+	c.epoch.Add(1)
+}
 func (c *cache) set(ty reflect.Type, sp *mockField) {
 	if ty.Kind() == reflect.Pointer {
 		ty = ty.Elem()
@@ -82,7 +102,11 @@ type mockField struct {
 	parent   FieldLevel   //*mockField
 	children []FieldLevel //[]*mockField
 
-	mf MockFunc
+	mf      MockFunc
+	wrapped MockFunc //mf wrapped by the middlewares, see mockFunc
+
+	mockKeys map[string]struct{} //only for the root, the mock functions used by the tree
+	tagKeys  map[string]struct{} //only for the root, the tag functions used by the tree
 }
 type FieldLevel interface {
 	GetIndex() int
@@ -135,25 +159,50 @@ func (m *Mock) Indirect(rt reflect.Type) (reflect.Type, bool) {
 	return rt, false
 }
 func (m *Mock) genCache(ctx context.Context, rv reflect.Value) (FieldLevel, error) {
+	if mf := m.cache.get(rv.Type()); mf != nil { //lock-free
+		return mf, nil
+	}
 	defer m.cache.lock.Unlock()
 	m.cache.lock.Lock()
 	mf := m.cache.get(rv.Type())
 	if mf != nil {
 		return mf, nil
 	}
+	m.Lock() //the factories and the tag rules are read during parsing
+	defer m.Unlock()
 	rt, isPtr := m.Indirect(rv.Type())
 	mf = &mockField{
-		rt:    rt,
-		rk:    rt.Kind(),
-		isPtr: isPtr,
+		rt:       rt,
+		rk:       rt.Kind(),
+		isPtr:    isPtr,
+		mockKeys: make(map[string]struct{}),
+		tagKeys:  make(map[string]struct{}),
 	}
 	err := m.parseStruct(ctx, mf, rt)
 	if err != nil {
 		return nil, err
 	}
+	m.wrapTree(mf)
 	m.cache.set(rt, mf)
 	return mf, nil
 }
+
+// root return the root of the tree, it records the functions used by the tree
+func (mf *mockField) root() *mockField {
+	root := mf
+	for root.parent != nil {
+		root = root.parent.(*mockField)
+	}
+	return root
+}
+
+// useMock set the mock function of the field and record it
+func (m *Mock) useMock(mf *mockField, key string) {
+	mf.mf = m.mockFactory[key]
+	if root := mf.root(); root.mockKeys != nil {
+		root.mockKeys[key] = struct{}{}
+	}
+}
 func (m *Mock) contactAlias(mf *mockField, alias string) {
 	filler, separator := "", ""
 	if mf.parent.GetKind() == reflect.Slice {
@@ -171,19 +220,24 @@ func (m *Mock) parseStruct(ctx context.Context, mf *mockField, rt reflect.Type)
 	if rt.Kind() == reflect.Pointer {
 		rt = rt.Elem()
 	}
-	var err error
+	var (
+		err error
+		tag string
+		ok  bool
+	)
 	for i := 0; i < rt.NumField(); i++ {
-		if rt.Field(i).Tag.Get(m.tag) == "" { //no mock tag,skip the field
+		if tag, ok = m.fieldTag(rt, rt.Field(i)); !ok && !isMockerField(rt.Field(i)) { //no mock tag,skip the field
 			continue
 		}
-		err = m.parseStructField(ctx, mf, i, rt.Field(i))
+		err = m.parseStructField(ctx, mf, i, rt.Field(i), tag)
 		if err != nil {
 			return err
 		}
 	}
 	return nil
 }
-func (m *Mock) parseStructField(ctx context.Context, parent *mockField, index int, rs reflect.StructField) error {
+func (m *Mock) parseStructField(ctx context.Context, parent *mockField, index int, rs reflect.StructField,
+	tag string) error {
 	mf := &mockField{
 		index:  index,
 		tags:   make(TagLevelMap),
@@ -196,7 +250,7 @@ func (m *Mock) parseStructField(ctx context.Context, parent *mockField, index in
 		return fmt.Errorf("not support the kind:%s", mf.rk.String())
 	}
 	m.contactAlias(mf, rs.Name)
-	err := m.parseTag(ctx, mf, rs.Tag)
+	err := m.parseTag(ctx, mf, tag)
 	if err == nil {
 		parent.children = append(parent.children, mf)
 	}
@@ -239,9 +293,9 @@ func (m *Mock) parseIntoStruct(ctx context.Context, parent *mockField, rt reflec
 		return fmt.Errorf("not support the kind:%s", mf.rk.String())
 	}
 	if mf.isPtr { //init struct ptr
-		mf.mf = m.mockFactory[makeStruct]
+		m.useMock(mf, makeStruct)
 	}
-	m.contactAlias(mf, rt.Name())
+	m.contactAlias(mf, "") //the element has the same alias as the other elements, eg: Books.0.Id
 	err := m.parseStructTag(ctx, mf)
 	if err == nil {
 		parent.children = append(parent.children, mf)
@@ -249,9 +303,13 @@ func (m *Mock) parseIntoStruct(ctx context.Context, parent *mockField, rt reflec
 	return err
 }
 func (m *Mock) genMockFunc(mf *mockField) error {
+	if !mf.tags.Key(MockKey).Exists() && isMocker(mf.rt) { //the type mocks itself
+		mf.mf = mockMocker
+		return nil
+	}
 	key := mf.tags.Key(MockKey).GetKey()
-	if fn, ok := m.mockFactory[key]; ok {
-		mf.mf = fn
+	if _, ok := m.mockFactory[key]; ok {
+		m.useMock(mf, key)
 		return nil
 	}
 	return fmt.Errorf("not found mock key type:%s", key)
@@ -275,6 +333,9 @@ func (m *Mock) genMockTag(mf *mockField) error {
 		if !ok {
 			return fmt.Errorf("not support the mock tag:%s", values[0])
 		}
+		if root := mf.root(); root.tagKeys != nil {
+			root.tagKeys[key] = struct{}{}
+		}
 		tl, err = fn(mf.rt, key, value)
 		if err != nil {
 			return err
@@ -294,12 +355,12 @@ func (m *Mock) parseSliceTag(ctx context.Context, mf *mockField) error {
 	if mf.tags.Key(MockSkip).Exists() {
 		return nil
 	}
-	if mf.tags.Key(MockKey).Exists() {
+	if mf.tags.Key(MockKey).Exists() || isMocker(mf.rt) {
 		if err := m.genMockFunc(mf); err != nil {
 			return err
 		}
 	} else {
-		mf.mf = m.mockFactory[makeSlice]
+		m.useMock(mf, makeSlice)
 	}
 	//for slice element
 	if !mf.tags.Key(MockInto).Exists() {
@@ -321,12 +382,12 @@ func (m *Mock) parseStructTag(ctx context.Context, mf *mockField) error {
 	if mf.tags.Key(MockSkip).Exists() {
 		return nil
 	}
-	if mf.tags.Key(MockKey).Exists() {
+	if mf.tags.Key(MockKey).Exists() || isMocker(mf.rt) {
 		if err := m.genMockFunc(mf); err != nil {
 			return err
 		}
 	} else if mf.isPtr { //init struct ptr
-		mf.mf = m.mockFactory[makeStruct]
+		m.useMock(mf, makeStruct)
 	}
 	//for struct element
 	if !mf.tags.Key(MockInto).Exists() {
@@ -343,8 +404,40 @@ func (m *Mock) parseBaseTag(mf *mockField) error {
 	}
 	return m.genMockFunc(mf)
 }
-func (m *Mock) parseTag(ctx context.Context, mf *mockField, tag reflect.StructTag) error {
-	mf.tempTags = m.splitTag(tag.Get(m.tag))
+
+// leafField parse the tags of the base type or slice out of struct, the elements of slice are not parsed
+func (m *Mock) leafField(rt reflect.Type, tags [][2]string) (*mockField, error) {
+	mf := &mockField{rt: rt, rk: rt.Kind(), tags: make(TagLevelMap)}
+	if _, ok := notSupportTypes[mf.rk]; ok || mf.rk == reflect.Struct {
+		return nil, fmt.Errorf("not support the kind:%s", mf.rk.String())
+	}
+	m.Lock()
+	defer m.Unlock()
+	for _, tag := range tags {
+		if tag[0] == MockInto {
+			break
+		}
+		fn, ok := m.tagFactory[tag[0]]
+		if !ok {
+			return nil, fmt.Errorf("not support the mock tag:%s", tag[0])
+		}
+		tl, err := fn(rt, tag[0], tag[1])
+		if err != nil {
+			return nil, err
+		}
+		mf.tags[tag[0]] = tl
+	}
+	if mf.rk == reflect.Slice && !mf.tags.Key(MockKey).Exists() {
+		mf.mf = m.mockFactory[makeSlice]
+	} else if err := m.genMockFunc(mf); err != nil {
+		return nil, err
+	}
+	m.wrapTree(mf)
+	return mf, nil
+}
+
+func (m *Mock) parseTag(ctx context.Context, mf *mockField, tag string) error {
+	mf.tempTags = m.splitTag(tag)
 	switch mf.rk {
 	case reflect.Slice:
 		return m.parseSliceTag(ctx, mf)
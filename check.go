@@ -0,0 +1,303 @@
+package gomock
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"reflect"
+	"time"
+)
+
+const (
+	defaultCheckCount     = 100
+	defaultCheckMaxShrink = 1000
+)
+
+// TestingT is the part of testing.TB used by Check
+type TestingT interface {
+	Helper()
+	Fatalf(format string, args ...any)
+}
+
+type CheckConfig struct {
+	Count     int    //the number of the values, default 100
+	Seed      *int64 //the value i is mocked by a random source seeded with *Seed+i, default the current time
+	MaxShrink int    //the max number of the shrinking steps, default 1000
+}
+
+// Check mock the values of T and call prop with them like testing/quick, if prop returns false or panics,
+// the value is shrunk toward the minimal value which still fails in the same way and keeps valid for its tags:
+// shorter strings, smaller numbers and fewer slice elements. the seed and the minimal value are reported by t.Fatalf.
+// T is a struct or a struct ptr, prop must not change the value
+func Check[T any](t TestingT, m *Mock, prop func(T) bool, config ...CheckConfig) {
+	t.Helper()
+	seed := time.Now().UnixNano()
+	cfg := CheckConfig{Count: defaultCheckCount, Seed: &seed, MaxShrink: defaultCheckMaxShrink}
+	if len(config) > 0 {
+		if config[0].Count > 0 {
+			cfg.Count = config[0].Count
+		}
+		if config[0].Seed != nil {
+			cfg.Seed = config[0].Seed
+		}
+		if config[0].MaxShrink > 0 {
+			cfg.MaxShrink = config[0].MaxShrink
+		}
+	}
+	seed = *cfg.Seed
+	r := rand.New(rand.NewSource(seed))
+	ctx := WithRand(context.Background(), r)
+	for i := 0; i < cfg.Count; i++ {
+		r.Seed(seed + int64(i))
+		value, err := makeOne[T](ctx, m)
+		if err != nil {
+			t.Fatalf("gomock: mock the value %d,seed:%d,err:%v", i, seed, err)
+			return
+		}
+		if ok, _ := holds(prop, value); ok {
+			continue
+		}
+		original := sprintValue(value)
+		value, steps, err := shrink(m, value, prop, cfg.MaxShrink)
+		if err != nil {
+			t.Fatalf("gomock: shrink the value %d,seed:%d,err:%v", i, seed, err)
+			return
+		}
+		t.Fatalf("gomock: property failed on the value %d,seed:%d\noriginal: %s\nshrunk by %d steps: %s",
+			i, seed, original, steps, sprintValue(value))
+		return
+	}
+}
+
+// holds call prop, panicked is true if prop panics, it is a failure too
+func holds[T any](prop func(T) bool, value T) (ok, panicked bool) {
+	defer func() {
+		if recover() != nil {
+			ok, panicked = false, true
+		}
+	}()
+	return prop(value), false
+}
+
+// sprintValue print the value as json, the ptr fields are followed
+func sprintValue(value any) string {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%+v", value)
+	}
+	return string(b)
+}
+
+// shrinkStep change one field to a smaller value
+type shrinkStep struct {
+	val reflect.Value //the field value as declared
+	fl  FieldLevel
+	rv  reflect.Value //the smaller value as declared
+}
+
+// shrink change the value step by step, every step keeps the value valid and failing in the same way,
+// a step making prop panic is rejected unless prop panics on the value, return the number of steps
+func shrink[T any](m *Mock, value T, prop func(T) bool, maxShrink int) (T, int, error) {
+	ptr := reflect.ValueOf(&value).Elem()
+	if ptr.Kind() != reflect.Pointer {
+		ptr = ptr.Addr()
+	}
+	if ptr.IsNil() {
+		return value, 0, errors.New("nil value")
+	}
+	fl, err := m.genCache(context.Background(), ptr)
+	if err != nil {
+		return value, 0, err
+	}
+	_, panicked := holds(prop, value)
+	fails := func() bool {
+		ok, p := holds(prop, value)
+		return !ok && p == panicked
+	}
+	steps := 0
+	for steps < maxShrink {
+		var candidates []shrinkStep
+		err = m.walkValue(ptr, fl, "", func(val reflect.Value, fl FieldLevel, _ string) error {
+			candidates = append(candidates, shrinkSteps(val, fl)...)
+			return nil
+		})
+		if err != nil {
+			return value, steps, err
+		}
+		shrunk := false
+		for _, step := range candidates {
+			if m.tryShrink(ptr, step, fails) {
+				shrunk = true
+				steps++
+				break
+			}
+		}
+		if !shrunk {
+			break
+		}
+	}
+	return value, steps, nil
+}
+
+// tryShrink set the smaller value, it is reverted unless the value is still valid and failing
+func (m *Mock) tryShrink(ptr reflect.Value, step shrinkStep, fails func() bool) bool {
+	old := reflect.New(step.val.Type()).Elem()
+	old.Set(step.val)
+	step.val.Set(step.rv)
+	if m.Validate(ptr.Interface()) == nil && fails() {
+		return true
+	}
+	step.val.Set(old)
+	return false
+}
+
+// shrinkSteps return the smaller values of the field, the most aggressive first. the ptr is never nil,
+// the built-in functions always allocate it. only the fields mocked by the built-in string, integer, decimal
+// and slice functions are shrunk
+func shrinkSteps(val reflect.Value, fl FieldLevel) []shrinkStep {
+	tm := fl.GetTags()
+	if len(tm) == 0 || tm.Key(MockSkip).Exists() || fl.IsPtr() && val.IsNil() {
+		return nil
+	}
+	switch key := tm.Key(MockKey).GetKey(); {
+	case key == makeString, key == makeInteger, key == makeDecimal, key == makeSlice:
+	case key == "" && fl.GetKind() == reflect.Slice: //mocked by the slice function by default
+	default:
+		return nil
+	}
+	value := val
+	if fl.IsPtr() {
+		value = val.Elem()
+	}
+	var values []reflect.Value
+	switch {
+	case fl.GetKind() == reflect.String:
+		values = shrinkString(tm, value)
+	case value.CanInt():
+		values = shrinkInteger(tm, value, value.Int())
+	case value.CanUint() && value.Uint() <= math.MaxInt64:
+		values = shrinkInteger(tm, value, int64(value.Uint()))
+	case value.CanFloat():
+		values = shrinkDecimal(tm, value)
+	case fl.GetKind() == reflect.Slice:
+		values = shrinkSlice(value)
+	}
+	steps := make([]shrinkStep, 0, len(values))
+	for _, rv := range values {
+		if fl.IsPtr() { //do not change the value shared by the ptr
+			ptr := reflect.New(fl.GetType())
+			ptr.Elem().Set(rv)
+			rv = ptr
+		}
+		steps = append(steps, shrinkStep{val: val, fl: fl, rv: rv})
+	}
+	return steps
+}
+
+func shrinkString(tm TagLevelMap, value reflect.Value) []reflect.Value {
+	var (
+		runes   = []rune(value.String())
+		strings []string
+	)
+	if options := tm.Key(MockOptions).GetStrSet(); len(options) > 0 && options[0] != value.String() {
+		strings = append(strings, options[0])
+	}
+	if len(runes) > 0 {
+		strings = append(strings, "", string(runes[:len(runes)/2]), string(runes[:len(runes)-1]))
+	}
+	values := make([]reflect.Value, 0, len(strings))
+	for _, str := range strings {
+		rv := reflect.New(value.Type()).Elem()
+		rv.SetString(str)
+		values = append(values, rv)
+	}
+	return values
+}
+
+// shrinkInteger move the integer toward zero or the lower bound
+func shrinkInteger(tm TagLevelMap, value reflect.Value, v int64) []reflect.Value {
+	var integers []int64
+	if options := tm.Key(MockOptions).GetInt64Set(); len(options) > 0 {
+		integers = append(integers, options[0])
+	}
+	integers = append(integers, 0, tm.Key(MockGte).GetInt64(), tm.Key(MockGt).GetInt64()+1, v/2)
+	if v > 0 {
+		integers = append(integers, v-1)
+	} else if v < 0 {
+		integers = append(integers, v+1)
+	}
+	values := make([]reflect.Value, 0, len(integers))
+	for _, integer := range integers {
+		if !smaller(integer, v) || value.CanUint() && integer < 0 {
+			continue
+		}
+		rv := reflect.New(value.Type()).Elem()
+		if rv.CanInt() {
+			rv.SetInt(integer)
+		} else {
+			rv.SetUint(uint64(integer))
+		}
+		values = append(values, rv)
+	}
+	return values
+}
+
+// shrinkDecimal move the decimal toward zero or the lower bound, and drop the fraction
+func shrinkDecimal(tm TagLevelMap, value reflect.Value) []reflect.Value {
+	var (
+		v          = value.Float()
+		conversion = decimalConversion(tm)
+		decimals   []float64
+	)
+	if options := tm.Key(MockOptions).GetFloat64Set(); len(options) > 0 {
+		decimals = append(decimals, options[0])
+	}
+	decimals = append(decimals, 0, tm.Key(MockGte).GetFloat64(), math.Trunc(v), math.Trunc(v/2*conversion)/conversion)
+	values := make([]reflect.Value, 0, len(decimals))
+	for _, decimal := range decimals {
+		if math.Abs(decimal) > math.Abs(v) || decimal == v {
+			continue
+		}
+		rv := reflect.New(value.Type()).Elem()
+		rv.SetFloat(decimal)
+		values = append(values, rv)
+	}
+	return values
+}
+
+// shrinkSlice remove the elements
+func shrinkSlice(value reflect.Value) []reflect.Value {
+	n := value.Len()
+	if n == 0 {
+		return nil
+	}
+	values := []reflect.Value{
+		reflect.MakeSlice(value.Type(), 0, 0),
+		value.Slice(0, n/2),
+		value.Slice(0, n-1),
+		value.Slice(1, n),
+	}
+	for i := range values { //do not share the array with the original
+		rv := reflect.MakeSlice(value.Type(), values[i].Len(), values[i].Len())
+		reflect.Copy(rv, values[i])
+		values[i] = rv
+	}
+	return values
+}
+
+// smaller report whether a is closer to zero than b
+func smaller(a, b int64) bool {
+	if a == b {
+		return false
+	}
+	if a >= 0 && b >= 0 {
+		return a < b
+	}
+	if a <= 0 && b <= 0 {
+		return a > b
+	}
+	return a > 0 && b < 0 && a < -b || a < 0 && b > 0 && -a < b
+}
@@ -0,0 +1,16 @@
+module github.com/pigfu/gomock/cmd/gomock
+
+// golang.org/x/tools v0.26.0 needs go 1.22.0, the older versions do not build with the current toolchains.
+// gomock itself stays on go 1.20.
+go 1.22.0
+
+require (
+	github.com/pigfu/gomock v0.1.0
+	golang.org/x/tools v0.26.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	golang.org/x/mod v0.21.0 // indirect
+	golang.org/x/sync v0.8.0 // indirect
+)
@@ -0,0 +1,75 @@
+// Command gomock is the command line of gomock.
+//
+//	gomock gen [-type User,Order] [-output mock_gen.go] [-tag mock] [dir]
+//	gomock print ./internal/model.User [-n 5] [-format json|yaml|ndjson] [-seed 42]
+//
+// gen generate the typed mock functions of the structs, it is go:generate friendly:
+//
+//	//go:generate go run github.com/pigfu/gomock/cmd/gomock gen -type User
+//
+// print load the package and print the mocked samples of the struct, the methods of the struct are not available.
+//
+// the command is a separate module, so golang.org/x/tools stays out of gomock, install it by
+// `go install github.com/pigfu/gomock/cmd/gomock@latest` or require it in the tools of the module to go run it.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pigfu/gomock/gen"
+)
+
+const usage = `usage: gomock <command> [flags]
+
+commands:
+  gen    generate the typed mock functions of the structs
+  print  print the mocked samples of a struct
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	var err error
+	switch os.Args[1] {
+	case "gen":
+		err = runGen(os.Args[2:])
+	case "print":
+		err = runPrint(os.Args[2:], os.Stdout)
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gomock %s: %v\n", os.Args[1], err)
+		os.Exit(1)
+	}
+}
+
+func runGen(args []string) error {
+	fs := flag.NewFlagSet("gen", flag.ExitOnError)
+	var (
+		types  = fs.String("type", "", "comma-separated struct names, default all structs with mock tags")
+		output = fs.String("output", "mock_gen.go", "output file name in the package directory")
+		tag    = fs.String("tag", "mock", "the tag name")
+	)
+	_ = fs.Parse(args)
+	dir := "."
+	if fs.NArg() > 0 {
+		dir = fs.Arg(0)
+	}
+	config := gen.Config{Dir: dir, Output: *output, Tag: *tag}
+	if *types != "" {
+		config.Types = strings.Split(*types, ",")
+	}
+	src, err := gen.Generate(config)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, *output), src, 0o644)
+}
@@ -0,0 +1,281 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"go/types"
+	"io"
+	"math/rand"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/pigfu/gomock"
+	"golang.org/x/tools/go/packages"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	formatJSON   = "json"
+	formatYAML   = "yaml"
+	formatNDJSON = "ndjson"
+)
+
+// the packages are type-checked from the sources, the export data of the toolchain may be newer than go/packages
+const loadMode = packages.NeedName | packages.NeedTypes | packages.NeedSyntax | packages.NeedImports | packages.NeedDeps
+
+var timeType = reflect.TypeOf(time.Time{})
+
+type printConfig struct {
+	pattern string //the package and the type, eg: ./internal/model.User
+	n       int
+	format  string
+	seed    int64
+	dir     string //the working directory of the package loading
+}
+
+func runPrint(args []string, w io.Writer) error {
+	fs := flag.NewFlagSet("print", flag.ContinueOnError)
+	config := printConfig{}
+	fs.IntVar(&config.n, "n", 1, "the number of samples")
+	fs.StringVar(&config.format, "format", formatJSON, "the output format: json, yaml or ndjson")
+	fs.Int64Var(&config.seed, "seed", time.Now().UnixNano(), "the random seed")
+	var patterns []string
+	for { //the flags are allowed after the type
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		patterns, args = append(patterns, fs.Arg(0)), fs.Args()[1:]
+	}
+	if len(patterns) != 1 {
+		return errors.New("expect one type, eg: gomock print ./internal/model.User")
+	}
+	config.pattern = patterns[0]
+	return printSamples(config, w)
+}
+
+func printSamples(config printConfig, w io.Writer) error {
+	rt, err := loadType(config.dir, config.pattern)
+	if err != nil {
+		return err
+	}
+	if config.n < 1 {
+		return fmt.Errorf("invalid n:%d", config.n)
+	}
+	var (
+		m       = gomock.New()
+		ctx     = gomock.WithRand(context.Background(), rand.New(rand.NewSource(config.seed)))
+		samples = make([]any, 0, config.n)
+	)
+	for i := 0; i < config.n; i++ {
+		ptr := reflect.New(rt)
+		if err = m.StructCtx(ctx, ptr.Interface()); err != nil {
+			return err
+		}
+		samples = append(samples, ptr.Interface())
+	}
+	switch config.format {
+	case formatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if config.n == 1 {
+			return encoder.Encode(samples[0])
+		}
+		return encoder.Encode(samples)
+	case formatNDJSON:
+		encoder := json.NewEncoder(w)
+		for _, sample := range samples {
+			if err = encoder.Encode(sample); err != nil {
+				return err
+			}
+		}
+		return nil
+	case formatYAML:
+		if config.n == 1 {
+			return writeYAML(w, samples[0])
+		}
+		return writeYAML(w, samples)
+	}
+	return fmt.Errorf("not support the format:%s", config.format)
+}
+
+// writeYAML write the value in yaml with the json names in the field order
+func writeYAML(w io.Writer, value any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	var node yaml.Node
+	if err = yaml.Unmarshal(data, &node); err != nil { //json is yaml
+		return err
+	}
+	blockStyle(&node)
+	buf := bufio.NewWriter(w)
+	encoder := yaml.NewEncoder(buf)
+	encoder.SetIndent(2)
+	if err = encoder.Encode(&node); err != nil {
+		return err
+	}
+	if err = encoder.Close(); err != nil {
+		return err
+	}
+	return buf.Flush()
+}
+
+func blockStyle(node *yaml.Node) {
+	node.Style &^= yaml.FlowStyle | yaml.DoubleQuotedStyle
+	for _, child := range node.Content {
+		blockStyle(child)
+	}
+}
+
+// loadType load the package and build the struct type from its go types, the methods are not available,
+// eg: Mocker and AfterMocker
+func loadType(dir, pattern string) (reflect.Type, error) {
+	path, name := ".", pattern
+	if i := strings.LastIndex(pattern, "."); i > strings.LastIndex(pattern, "/") && i > 0 {
+		path, name = pattern[:i], pattern[i+1:]
+	} else if i == 0 { //the type in the current package, eg: .User
+		name = pattern[1:]
+	}
+	pkgs, err := packages.Load(&packages.Config{Mode: loadMode, Dir: dir}, path)
+	if err != nil {
+		return nil, err
+	}
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("expect one package for %s, found %d", path, len(pkgs))
+	}
+	if len(pkgs[0].Errors) > 0 {
+		return nil, pkgs[0].Errors[0]
+	}
+	obj := pkgs[0].Types.Scope().Lookup(name)
+	if obj == nil {
+		return nil, fmt.Errorf("not found the type:%s in %s", name, pkgs[0].PkgPath)
+	}
+	if _, ok := obj.Type().Underlying().(*types.Struct); !ok {
+		return nil, fmt.Errorf("not a struct:%s", name)
+	}
+	return (&typeBuilder{}).build(obj.Type())
+}
+
+// typeBuilder build the reflect type of the go type, nil for the unsupported types, eg: chan, func and complex.
+// the recursive fields and the fields of the unsupported types are dropped
+type typeBuilder struct {
+	stack []types.Type
+}
+
+func (tb *typeBuilder) build(t types.Type) (reflect.Type, error) {
+	if named, ok := t.(*types.Named); ok {
+		obj := named.Obj()
+		if obj.Pkg() != nil && obj.Pkg().Path() == "time" && obj.Name() == "Time" {
+			return timeType, nil
+		}
+	}
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		return basicTypes[u.Kind()], nil
+	case *types.Pointer:
+		elem, err := tb.build(u.Elem())
+		if err != nil || elem == nil {
+			return nil, err
+		}
+		return reflect.PointerTo(elem), nil
+	case *types.Slice:
+		elem, err := tb.build(u.Elem())
+		if err != nil || elem == nil {
+			return nil, err
+		}
+		return reflect.SliceOf(elem), nil
+	case *types.Array:
+		elem, err := tb.build(u.Elem())
+		if err != nil || elem == nil {
+			return nil, err
+		}
+		return reflect.ArrayOf(int(u.Len()), elem), nil
+	case *types.Map:
+		key, err := tb.build(u.Key())
+		if err != nil {
+			return nil, err
+		}
+		elem, err := tb.build(u.Elem())
+		if err != nil || key == nil || elem == nil {
+			return nil, err
+		}
+		return reflect.MapOf(key, elem), nil
+	case *types.Interface:
+		return reflect.TypeOf((*any)(nil)).Elem(), nil
+	case *types.Struct:
+		return tb.structType(t, u)
+	}
+	return nil, nil
+}
+
+func (tb *typeBuilder) structType(t types.Type, st *types.Struct) (reflect.Type, error) {
+	tb.stack = append(tb.stack, t)
+	defer func() { tb.stack = tb.stack[:len(tb.stack)-1] }()
+	fields := make([]reflect.StructField, 0, st.NumFields())
+	for i := 0; i < st.NumFields(); i++ {
+		field := st.Field(i)
+		if !field.Exported() || tb.recursive(field.Type()) {
+			continue
+		}
+		rt, err := tb.build(field.Type())
+		if err != nil {
+			return nil, fmt.Errorf("field:%s,err:%v", field.Name(), err)
+		}
+		if rt == nil {
+			continue
+		}
+		fields = append(fields, reflect.StructField{
+			Name:      field.Name(),
+			Type:      rt,
+			Tag:       reflect.StructTag(st.Tag(i)),
+			Anonymous: field.Embedded() && rt.Kind() == reflect.Struct,
+		})
+	}
+	return reflect.StructOf(fields), nil
+}
+
+// recursive report whether the type refers the structs being built
+func (tb *typeBuilder) recursive(t types.Type) bool {
+	switch u := t.(type) {
+	case *types.Pointer:
+		return tb.recursive(u.Elem())
+	case *types.Slice:
+		return tb.recursive(u.Elem())
+	case *types.Array:
+		return tb.recursive(u.Elem())
+	case *types.Map:
+		return tb.recursive(u.Elem())
+	}
+	for _, parent := range tb.stack {
+		if types.Identical(parent, t) {
+			return true
+		}
+	}
+	return false
+}
+
+var basicTypes = map[types.BasicKind]reflect.Type{
+	types.Bool:    reflect.TypeOf(false),
+	types.Int:     reflect.TypeOf(0),
+	types.Int8:    reflect.TypeOf(int8(0)),
+	types.Int16:   reflect.TypeOf(int16(0)),
+	types.Int32:   reflect.TypeOf(int32(0)),
+	types.Int64:   reflect.TypeOf(int64(0)),
+	types.Uint:    reflect.TypeOf(uint(0)),
+	types.Uint8:   reflect.TypeOf(uint8(0)),
+	types.Uint16:  reflect.TypeOf(uint16(0)),
+	types.Uint32:  reflect.TypeOf(uint32(0)),
+	types.Uint64:  reflect.TypeOf(uint64(0)),
+	types.Float32: reflect.TypeOf(float32(0)),
+	types.Float64: reflect.TypeOf(float64(0)),
+	types.String:  reflect.TypeOf(""),
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+const bookType = "github.com/pigfu/gomock/gen/internal/example.Book"
+
+func TestPrint(t *testing.T) {
+	run := func(args ...string) string {
+		t.Helper()
+		out := &bytes.Buffer{}
+		if err := runPrint(args, out); err != nil {
+			t.Fatal(err)
+		}
+		return out.String()
+	}
+	var books []map[string]any
+	out := run(bookType, "-n", "3", "--format", "json", "--seed", "42")
+	if err := json.Unmarshal([]byte(out), &books); err != nil || len(books) != 3 {
+		t.Fatalf("unexpected json: %s, %v", out, err)
+	}
+	if id := books[0]["Id"].(float64); id < 1 || id > 100 {
+		t.Errorf("unexpected id: %v", id)
+	}
+	if again := run(bookType, "-n", "3", "-seed", "42"); again != out {
+		t.Errorf("expect the same samples by the same seed:\n%s\n%s", out, again)
+	}
+	if lines := strings.Split(strings.TrimSpace(run(bookType, "-n", "3", "-format", "ndjson")), "\n"); len(lines) != 3 {
+		t.Errorf("unexpected ndjson: %v", lines)
+	}
+	out = run(bookType, "-n", "2", "-format", "yaml")
+	if err := yaml.Unmarshal([]byte(out), &books); err != nil || len(books) != 2 || books[0]["Title"] == nil {
+		t.Fatalf("unexpected yaml: %s, %v", out, err)
+	}
+
+	for _, args := range [][]string{
+		{},
+		{bookType, "-format", "xml"},
+		{"github.com/pigfu/gomock/gen/internal/example.Level"},
+		{"github.com/pigfu/gomock/gen/internal/example.Unknown"},
+	} {
+		if err := runPrint(args, &bytes.Buffer{}); err == nil {
+			t.Errorf("expect error for %v", args)
+		}
+	}
+}
+
+func TestTypeBuilder(t *testing.T) {
+	const src = `package model
+import "time"
+type Node struct {
+	Name     string ` + "`json:\"name\" mock:\"key=string,eq=node\"`" + `
+	Created  time.Time
+	Children []*Node
+	Parent   *Node
+	Events   chan int
+	Meta     map[string]int
+	secret   string
+}`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "model.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg, err := (&types.Config{Importer: importer.ForCompiler(fset, "source", nil)}).
+		Check("model", fset, []*ast.File{file}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rt, err := (&typeBuilder{}).build(pkg.Scope().Lookup("Node").Type())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for i := 0; i < rt.NumField(); i++ {
+		names = append(names, rt.Field(i).Name)
+	}
+	if strings.Join(names, ",") != "Name,Created,Meta" || rt.Field(1).Type != timeType {
+		t.Errorf("unexpected fields: %v", names)
+	}
+	if rt.Field(0).Tag.Get("mock") != "key=string,eq=node" {
+		t.Errorf("unexpected tag: %s", rt.Field(0).Tag)
+	}
+}
@@ -0,0 +1,215 @@
+package gomock
+
+import (
+	"context"
+	"encoding"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// WriteNDJSON mock n values of T and write them as newline delimited json one by one, see MakeN
+func WriteNDJSON[T any](ctx context.Context, m *Mock, w io.Writer, n int) error {
+	encoder := json.NewEncoder(w)
+	return writeN[T](ctx, m, n, func(value T) error {
+		return encoder.Encode(value)
+	})
+}
+
+// WriteYAML mock n values of T and write them as yaml documents one by one, see MakeN
+func WriteYAML[T any](ctx context.Context, m *Mock, w io.Writer, n int) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	err := writeN[T](ctx, m, n, func(value T) error {
+		return encoder.Encode(value)
+	})
+	if err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// WriteCSV mock n values of T and write them as csv one by one, see MakeN. the columns are the mocked fields in the field
+// order named by the aliases, the nested fields are flattened, eg: "Hobby.Name", and the slice elements are indexed,
+// eg: "Hobbies.0.Name", "Hobbies.1.Name", up to the max length of the tags or WithSize, the longer slice is an error.
+// the slices without the max length, the structs without mocked fields and the other composite values are written as json
+func WriteCSV[T any](ctx context.Context, m *Mock, w io.Writer, n int) error {
+	rt := reflect.TypeOf((*T)(nil)).Elem()
+	if rt, _ = m.Indirect(rt); rt.Kind() != reflect.Struct {
+		return errors.New("not a struct or struct ptr")
+	}
+	fl, err := m.genCache(ctx, reflect.New(rt))
+	if err != nil {
+		return err
+	}
+	var (
+		size, sized = getSize(ctx)
+		columns     = csvColumns(fl, "", nil, nil, size, sized)
+		writer      = csv.NewWriter(w)
+		record      = make([]string, len(columns))
+	)
+	for i, column := range columns {
+		record[i] = column.name
+	}
+	if err = writer.Write(record); err != nil {
+		return err
+	}
+	err = writeN[T](ctx, m, n, func(value T) error {
+		rv := reflect.ValueOf(value)
+		for i, column := range columns {
+			cell, err := column.cell(rv)
+			if err != nil {
+				return err
+			}
+			record[i] = cell
+		}
+		return writer.Write(record)
+	})
+	if err != nil {
+		return err
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func writeN[T any](ctx context.Context, m *Mock, n int, write func(T) error) error {
+	for i := 0; i < n; i++ {
+		value, err := makeOne[T](ctx, m)
+		if err != nil {
+			return err
+		}
+		if err = write(value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// csvColumn is a flattened field, path is the field indexes and the element indexes from the root,
+// limits is the element columns of the slices in the path, 0 for the fields
+type csvColumn struct {
+	name   string
+	path   []int
+	limits []int
+}
+
+func csvColumns(fl FieldLevel, prefix string, path, limits []int, size int, sized bool) []csvColumn {
+	var columns []csvColumn
+	for _, child := range fl.GetChildren() {
+		name, childPath, childLimits := prefix+child.GetName(), appendPath(path, child.GetIndex()), appendPath(limits, 0)
+		n, bounded := 0, false
+		if child.GetKind() == reflect.Slice {
+			n, bounded = csvElems(child, size, sized)
+		}
+		switch {
+		case child.GetKind() == reflect.Struct && len(child.GetChildren()) > 0:
+			columns = append(columns, csvColumns(child, name+".", childPath, childLimits, size, sized)...)
+		case bounded:
+			var elem FieldLevel
+			if len(child.GetChildren()) > 0 {
+				elem = child.GetChildren()[0]
+			}
+			for i := 0; i < n; i++ {
+				elemName, elemPath, elemLimits := name+"."+strconv.Itoa(i), appendPath(childPath, i), appendPath(childLimits, n)
+				if elem != nil && elem.GetKind() == reflect.Struct && len(elem.GetChildren()) > 0 {
+					columns = append(columns, csvColumns(elem, elemName+".", elemPath, elemLimits, size, sized)...)
+				} else {
+					columns = append(columns, csvColumn{name: elemName, path: elemPath, limits: elemLimits})
+				}
+			}
+		default: //the slice without the max length is a json cell
+			columns = append(columns, csvColumn{name: name, path: childPath, limits: childLimits})
+		}
+	}
+	return columns
+}
+
+// csvElems return the max length of the slice by the tags as sliceLength and randLength, bounded is false
+// if the slice is not mocked by the built-in function, or the length has no upper limit, eg: only gte, or no length tags
+func csvElems(fl FieldLevel, size int, sized bool) (int, bool) {
+	if fn := fl.GetMockFunc(); fn == nil || reflect.ValueOf(fn).Pointer() != reflect.ValueOf(mockSlice).Pointer() {
+		return 0, false
+	}
+	tm := fl.GetTags()
+	if eq := tm.Key(MockEqual).GetInt(); eq > 0 {
+		return eq, true
+	}
+	gte, gteExists := makeGteVal(reflect.Uint8, tm.Key(MockGt).GetInt(), tm.Key(MockGte).GetInt(),
+		tm.Key(MockGt).Exists(), tm.Key(MockGte).Exists())
+	lt, ltExists := makeLtVal(reflect.Uint8, tm.Key(MockLt).GetInt(), tm.Key(MockLte).GetInt(),
+		tm.Key(MockLt).Exists(), tm.Key(MockLte).Exists())
+	switch {
+	case !gteExists && !ltExists || gte < 0 || lt <= 0 || gte >= lt: //always empty unless overridden
+		return 0, false
+	case sized && size+1 < lt:
+		return maxFunc(gte, size), true
+	case ltExists:
+		return lt - 1, true
+	}
+	return 0, false
+}
+
+func appendPath(path []int, index int) []int {
+	return append(append(make([]int, 0, len(path)+1), path...), index)
+}
+
+// cell return the text of the column, empty if the value is absent, eg: nil ptr, short slice
+func (c csvColumn) cell(rv reflect.Value) (string, error) {
+	for i, index := range c.path {
+		for rv.Kind() == reflect.Pointer {
+			if rv.IsNil() {
+				return "", nil
+			}
+			rv = rv.Elem()
+		}
+		if rv.Kind() == reflect.Slice {
+			if rv.Len() > c.limits[i] {
+				return "", fmt.Errorf("field:%s,err:the length %d is greater than the columns %d", c.name, rv.Len(), c.limits[i])
+			}
+			if index >= rv.Len() {
+				return "", nil
+			}
+			rv = rv.Index(index)
+			continue
+		}
+		rv = rv.Field(index)
+	}
+	return cellText(rv)
+}
+
+func cellText(rv reflect.Value) (string, error) {
+	if rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return "", nil
+		}
+		if !rv.Type().Implements(textMarshalerType) {
+			rv = rv.Elem()
+		}
+	}
+	if rv.Type().Implements(textMarshalerType) {
+		text, err := rv.Interface().(encoding.TextMarshaler).MarshalText()
+		return string(text), err
+	}
+	switch {
+	case rv.Kind() == reflect.String:
+		return rv.String(), nil
+	case rv.Kind() == reflect.Bool:
+		return strconv.FormatBool(rv.Bool()), nil
+	case rv.CanInt():
+		return strconv.FormatInt(rv.Int(), 10), nil
+	case rv.CanUint():
+		return strconv.FormatUint(rv.Uint(), 10), nil
+	case rv.CanFloat():
+		return strconv.FormatFloat(rv.Float(), 'f', -1, rv.Type().Bits()), nil
+	}
+	data, err := json.Marshal(rv.Interface())
+	return string(data), err
+}
+
+var textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
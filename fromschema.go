@@ -0,0 +1,390 @@
+package gomock
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	maxSchemaDepth      = 8 //the recursive $ref stops here
+	defaultSchemaRange  = 1000
+	defaultSchemaLength = 10
+	defaultSchemaItems  = 3
+)
+
+// SchemaDoc is a JSON Schema or an OpenAPI document, Definitions holds $defs, definitions or components.schemas
+type SchemaDoc struct {
+	Schema
+	Definitions map[string]*Schema
+}
+
+// LoadSchema load the JSON Schema or OpenAPI document from a json or yaml file
+func LoadSchema(path string) (*SchemaDoc, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSchema(data)
+}
+
+// ParseSchema parse the JSON Schema or OpenAPI document in json or yaml, the type arrays take their first type except null
+func ParseSchema(data []byte) (*SchemaDoc, error) {
+	var doc struct {
+		Schema      `yaml:",inline"`
+		Defs        map[string]*Schema `json:"$defs" yaml:"$defs"`
+		Definitions map[string]*Schema `json:"definitions" yaml:"definitions"`
+		Components  struct {
+			Schemas map[string]*Schema `json:"schemas" yaml:"schemas"`
+		} `json:"components" yaml:"components"`
+	}
+	var err error
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		var raw any
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber() //the numbers are encoded again as they are
+		if err = decoder.Decode(&raw); err == nil {
+			if data, err = json.Marshal(jsonSchemaType(raw)); err == nil {
+				err = json.Unmarshal(data, &doc)
+			}
+		}
+	} else {
+		var node yaml.Node
+		if err = yaml.Unmarshal(data, &node); err == nil {
+			yamlSchemaType(&node)
+			err = node.Decode(&doc)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	sd := &SchemaDoc{Schema: doc.Schema, Definitions: make(map[string]*Schema)}
+	for _, defs := range []map[string]*Schema{doc.Definitions, doc.Defs, doc.Components.Schemas} {
+		for name, schema := range defs {
+			sd.Definitions[name] = schema
+		}
+	}
+	return sd, nil
+}
+
+// jsonSchemaType replace the type arrays by their first type except null, eg: ["string","null"] -> "string",
+// the mocked value is never null
+func jsonSchemaType(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			if types, ok := value.([]any); ok && key == "type" {
+				v[key] = schemaNull
+				for _, t := range types {
+					if t, ok := t.(string); ok && t != schemaNull {
+						v[key] = t
+						break
+					}
+				}
+				continue
+			}
+			v[key] = jsonSchemaType(value)
+		}
+	case []any:
+		for i := range v {
+			v[i] = jsonSchemaType(v[i])
+		}
+	}
+	return v
+}
+
+// yamlSchemaType replace the type sequences by their first type except null, see jsonSchemaType
+func yamlSchemaType(node *yaml.Node) {
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if key.Value != "type" || value.Kind != yaml.SequenceNode {
+				continue
+			}
+			t := schemaNull
+			for _, item := range value.Content {
+				if item.Value != schemaNull {
+					t = item.Value
+					break
+				}
+			}
+			node.Content[i+1] = &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: t}
+		}
+	}
+	for _, child := range node.Content {
+		yamlSchemaType(child)
+	}
+}
+
+// MockSchema mock the value of the schema named in the definitions, the root schema if the name is empty.
+// the value is map[string]any, []any, string, int64, float64, bool or nil. the strings, integers and decimals are mocked
+// by the mock functions with the tags translated from the schema, eg: format email by email, pattern by reg
+func (m *Mock) MockSchema(ctx context.Context, doc *SchemaDoc, name string) (any, error) {
+	schema := &doc.Schema
+	if name != "" {
+		var ok bool
+		if schema, ok = doc.Definitions[name]; !ok {
+			return nil, fmt.Errorf("not found the schema:%s", name)
+		}
+	}
+	return m.mockSchema(ctx, doc, schema, 0)
+}
+
+// MockSchemaJSON mock the json of the schema, see MockSchema
+func (m *Mock) MockSchemaJSON(ctx context.Context, doc *SchemaDoc, name string) (json.RawMessage, error) {
+	value, err := m.MockSchema(ctx, doc, name)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(value)
+}
+
+func (m *Mock) mockSchema(ctx context.Context, doc *SchemaDoc, schema *Schema, depth int) (any, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if schema == nil {
+		return nil, nil
+	}
+	if schema.Ref != "" {
+		if depth >= maxSchemaDepth {
+			return nil, nil
+		}
+		ref, ok := doc.Definitions[schema.Ref[strings.LastIndex(schema.Ref, "/")+1:]]
+		if !ok {
+			return nil, fmt.Errorf("not found the schema:%s", schema.Ref)
+		}
+		return m.mockSchema(ctx, doc, ref, depth+1)
+	}
+	r := GetRand(ctx)
+	switch {
+	case schema.Const != nil:
+		return schema.Const, nil
+	case len(schema.Enum) > 0:
+		return schema.Enum[r.Intn(len(schema.Enum))], nil
+	case len(schema.OneOf) > 0:
+		return m.mockSchema(ctx, doc, schema.OneOf[r.Intn(len(schema.OneOf))], depth)
+	case len(schema.AnyOf) > 0:
+		return m.mockSchema(ctx, doc, schema.AnyOf[r.Intn(len(schema.AnyOf))], depth)
+	case len(schema.AllOf) > 0:
+		return m.mockAllOf(ctx, doc, schema, depth)
+	}
+	switch schemaType(schema) {
+	case "object":
+		return m.mockObject(ctx, doc, schema, depth)
+	case "array":
+		return m.mockArray(ctx, doc, schema, depth)
+	case "boolean":
+		return r.Intn(2) == 1, nil
+	case "string", "integer", "number":
+		return m.mockSchemaValue(ctx, schema)
+	}
+	return nil, nil
+}
+
+// schemaType infer the type if it is omitted
+func schemaType(schema *Schema) string {
+	switch {
+	case schema.Type != "":
+		return schema.Type
+	case schema.Properties != nil:
+		return "object"
+	case schema.Items != nil:
+		return "array"
+	case schema.Pattern != "" || schema.Format != "" || schema.MinLength != nil || schema.MaxLength != nil:
+		return "string"
+	case schema.Minimum != nil || schema.Maximum != nil || schema.ExclusiveMinimum != nil || schema.ExclusiveMaximum != nil:
+		return "number"
+	}
+	return ""
+}
+
+// the properties are mocked in order, the same random source gives the same object
+func (m *Mock) mockObject(ctx context.Context, doc *SchemaDoc, schema *Schema, depth int) (map[string]any, error) {
+	names := make([]string, 0, len(schema.Properties))
+	for name := range schema.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	object := make(map[string]any, len(names))
+	for _, name := range names {
+		value, err := m.mockSchema(ctx, doc, schema.Properties[name], depth+1)
+		if err != nil {
+			return nil, fmt.Errorf("field:%s,err:%w", name, err)
+		}
+		object[name] = value
+	}
+	return object, nil
+}
+
+func (m *Mock) mockAllOf(ctx context.Context, doc *SchemaDoc, schema *Schema, depth int) (any, error) {
+	object := make(map[string]any)
+	for _, sub := range schema.AllOf {
+		value, err := m.mockSchema(ctx, doc, sub, depth)
+		if err != nil {
+			return nil, err
+		}
+		values, ok := value.(map[string]any)
+		if !ok { //not an object, the first wins
+			return value, nil
+		}
+		for name, v := range values {
+			object[name] = v
+		}
+	}
+	return object, nil
+}
+
+func (m *Mock) mockArray(ctx context.Context, doc *SchemaDoc, schema *Schema, depth int) ([]any, error) {
+	gte, lte := 0, -1
+	if schema.MinItems != nil {
+		gte = *schema.MinItems
+	}
+	if schema.MaxItems != nil {
+		lte = *schema.MaxItems
+	}
+	if lte < gte {
+		lte = gte + defaultSchemaItems
+	}
+	n := randLength(ctx, gte, lte+1)
+	values := make([]any, 0, n)
+	for i := 0; i < n; i++ {
+		value, err := m.mockSchema(ctx, doc, schema.Items, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// mockSchemaValue mock the string, integer or number by the mock function with the translated tags
+func (m *Mock) mockSchemaValue(ctx context.Context, schema *Schema) (any, error) {
+	fl, err := m.schemaLeaf(schema)
+	if err != nil {
+		return nil, err
+	}
+	fn := m.mockFunc(fl)
+	if fn == nil {
+		return nil, nil
+	}
+	rv, err := fn(ctx, fl)
+	if err != nil {
+		return nil, err
+	}
+	return rv.Interface(), nil
+}
+
+// schemaLeaf build the field of the schema, the tags are parsed by the tag functions
+func (m *Mock) schemaLeaf(schema *Schema) (FieldLevel, error) {
+	var (
+		rt   reflect.Type
+		tags [][2]string
+	)
+	switch schemaType(schema) {
+	case "string":
+		rt, tags = reflect.TypeOf(""), stringTags(schema)
+	case "integer":
+		rt, tags = reflect.TypeOf(int64(0)), numberTags(schema, makeInteger, true)
+	default:
+		rt, tags = reflect.TypeOf(float64(0)), numberTags(schema, makeDecimal, false)
+	}
+	return m.leafField(rt, tags)
+}
+
+func stringTags(schema *Schema) [][2]string {
+	switch schema.Format {
+	case "email":
+		return [][2]string{{MockKey, makeEmail}}
+	case "date-time":
+		return [][2]string{{MockKey, makeTime}, {MockTime, time.RFC3339}}
+	case "date":
+		return [][2]string{{MockKey, makeTime}, {MockTime, time.DateOnly}}
+	}
+	if schema.Pattern != "" {
+		return [][2]string{{MockKey, makeString}, {MockRegExp, schema.Pattern}}
+	}
+	gte, lte := 0, -1
+	if schema.MinLength != nil {
+		gte = *schema.MinLength
+	}
+	if schema.MaxLength != nil {
+		lte = *schema.MaxLength
+	}
+	if lte < gte {
+		lte = gte + defaultSchemaLength
+	}
+	return [][2]string{{MockKey, makeString}, {MockGte, strconv.Itoa(gte)}, {MockLte, strconv.Itoa(lte)}}
+}
+
+// numberTags translate the bounds, the missing bound is 1000 away from the other.
+// the exclusive bounds of the integer become inclusive, eg: exclusiveMinimum 5 -> gte=6
+func numberTags(schema *Schema, key string, integer bool) [][2]string {
+	format := func(v float64) string {
+		if integer {
+			return strconv.FormatInt(int64(v), 10)
+		}
+		if v == math.Trunc(v*100)/100 { //keep two decimals at least
+			return strconv.FormatFloat(v, 'f', 2, 64)
+		}
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	var (
+		tags     = [][2]string{{MockKey, key}}
+		min, max *float64
+	)
+	for _, bound := range []struct {
+		tag   string
+		value *float64
+		up    bool
+	}{
+		{MockGte, schema.Minimum, false}, {MockGt, schema.ExclusiveMinimum, false},
+		{MockLte, schema.Maximum, true}, {MockLt, schema.ExclusiveMaximum, true},
+	} {
+		if bound.value == nil {
+			continue
+		}
+		tag, value := bound.tag, *bound.value
+		if integer {
+			tag, value = integerBound(tag, value)
+		}
+		if bound.up {
+			max = &value
+		} else {
+			min = &value
+		}
+		tags = append(tags, [2]string{tag, format(value)})
+	}
+	switch {
+	case min == nil && max == nil:
+		tags = append(tags, [2]string{MockGte, format(0)}, [2]string{MockLte, format(defaultSchemaRange)})
+	case min == nil:
+		tags = append(tags, [2]string{MockGte, format(*max - defaultSchemaRange)})
+	case max == nil:
+		tags = append(tags, [2]string{MockLte, format(*min + defaultSchemaRange)})
+	}
+	return tags
+}
+
+// integerBound return the inclusive integer bound, eg: gt=5 -> gte=6, lte=9.5 -> lte=9
+func integerBound(tag string, v float64) (string, float64) {
+	switch tag {
+	case MockGt:
+		return MockGte, math.Floor(v) + 1
+	case MockLt:
+		return MockLte, math.Ceil(v) - 1
+	case MockGte:
+		return tag, math.Ceil(v)
+	}
+	return tag, math.Floor(v)
+}
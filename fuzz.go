@@ -0,0 +1,43 @@
+package gomock
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math/rand"
+)
+
+// byteSource read the random numbers from the bytes of the fuzzer, 8 bytes per number.
+// it gives zero once the bytes are exhausted, so the shorter input gives the smaller values
+type byteSource struct {
+	data []byte
+}
+
+func (bs *byteSource) Uint64() uint64 {
+	var b [8]byte
+	n := copy(b[:], bs.data)
+	bs.data = bs.data[n:]
+	return binary.BigEndian.Uint64(b[:])
+}
+func (bs *byteSource) Int63() int64 { return int64(bs.Uint64() & (1<<63 - 1)) }
+func (bs *byteSource) Seed(_ int64) {}
+
+// FromBytes mock the value of T by the bytes of the fuzzer instead of the random source,
+// the same bytes give the same value which keeps valid for its tags, T is a struct or a struct ptr.
+// it panics if the value can not be mocked, it is used in the fuzz target:
+//
+//	f.Fuzz(func(t *testing.T, data []byte) {
+//		user := gomock.FromBytes[User](m, data)
+//	})
+func FromBytes[T any](m *Mock, data []byte) T {
+	value, err := FromBytesCtx[T](context.Background(), m, data)
+	if err != nil {
+		panic(fmt.Sprintf("gomock: mock from bytes,err:%v", err))
+	}
+	return value
+}
+
+// FromBytesCtx is FromBytes with the context, it returns the error instead of panic
+func FromBytesCtx[T any](ctx context.Context, m *Mock, data []byte) (T, error) {
+	return makeOne[T](WithRand(ctx, rand.New(&byteSource{data: data})), m)
+}
@@ -0,0 +1,248 @@
+package gomock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync/atomic"
+)
+
+// GenStruct is the prepared field tree of a struct for the code generated by `gomock gen`, the fields are keyed by the alias.
+// the generated code mocks the fields in the same order by the same mock functions as StructCtx without walking the struct,
+// so the same random source gives the same value.
+type GenStruct struct {
+	root   *mockField
+	fields map[string]*GenField
+}
+
+// GenField is a prepared field, the built-in mock functions are called directly without reflect.Value
+type GenField struct {
+	m       *Mock
+	fl      FieldLevel
+	builtin string //the key of the built-in mock function, empty for the others or if there are middlewares
+}
+
+// GenCache hold the fields of a struct resolved for the generated code, they are resolved once for the Mock
+// and again only after the registrations invalidate the parsed trees, so the generated code neither parses
+// the struct nor looks up the fields in the calls. the last Mock is kept only.
+type GenCache[T any] struct {
+	aliases []string //the fields mocked by the generated code
+	resolve func(gs *GenStruct) *T
+	value   atomic.Pointer[genResolved[T]]
+}
+
+type genResolved[T any] struct {
+	m      *Mock
+	epoch  uint64
+	fields *T //nil if the struct is mocked by StructCtx
+}
+
+// NewGenCache return the cache of the fields resolved by resolve, aliases are the fields mocked by the generated code
+func NewGenCache[T any](resolve func(gs *GenStruct) *T, aliases ...string) *GenCache[T] {
+	return &GenCache[T]{aliases: aliases, resolve: resolve}
+}
+
+// Load return the resolved fields of the struct ptr s. nil if the struct must be mocked by StructCtx:
+// the tree has the mocked fields unknown to the generated code, eg: the untagged fields given by the tag config,
+// the tag sources or the Mocker, or ctx has the overrides, WithFillZero or WithTrace
+func (gc *GenCache[T]) Load(ctx context.Context, m *Mock, s any) (*T, error) {
+	if hasOverride(ctx) || hasTrace(ctx) {
+		return nil, nil
+	}
+	if fillZero, _ := ctx.Value(fillZeroKey{}).(bool); fillZero {
+		return nil, nil
+	}
+	epoch := m.cache.epoch.Load() //loaded first, the registrations during resolving load again
+	if resolved := gc.value.Load(); resolved != nil && resolved.m == m && resolved.epoch == epoch {
+		return resolved.fields, nil
+	}
+	gs, err := m.GenStruct(s)
+	if err != nil {
+		return nil, err
+	}
+	resolved := &genResolved[T]{m: m, epoch: epoch}
+	if gs.covered(gc.aliases) {
+		resolved.fields = gc.resolve(gs)
+	}
+	gc.value.Store(resolved)
+	return resolved.fields, nil
+}
+
+// the built-in mock functions called directly by the generated code
+var genBuiltins = []string{makeSlice, makeStruct, makeString, makeInteger, makeDecimal}
+
+// GenStruct return the prepared fields of the struct, it is rebuilt once the cached tree is invalidated by the registrations
+func (m *Mock) GenStruct(s any) (*GenStruct, error) {
+	rt := reflect.TypeOf(s)
+	if rt != nil {
+		rt, _ = m.Indirect(rt)
+	}
+	if rt == nil || rt.Kind() != reflect.Struct {
+		return nil, errors.New("not a struct or struct ptr")
+	}
+	fl, err := m.genCache(context.Background(), reflect.New(rt))
+	if err != nil {
+		return nil, err
+	}
+	root := fl.(*mockField)
+	if value, ok := m.genStructs.Load(rt); ok && value.(*GenStruct).root == root {
+		return value.(*GenStruct), nil
+	}
+	gs := &GenStruct{root: root, fields: make(map[string]*GenField)}
+	gs.add(m, root, m.middlewares.Load() != nil)
+	m.genStructs.Store(rt, gs)
+	return gs, nil
+}
+
+// add the fields, the built-in mock functions are called directly if there are no middlewares,
+// Use invalidates the trees so the fields are added again
+func (gs *GenStruct) add(m *Mock, fl FieldLevel, wrapped bool) {
+	for _, child := range fl.GetChildren() {
+		gf := &GenField{m: m, fl: child}
+		if fn := child.GetMockFunc(); fn != nil && !wrapped {
+			for _, key := range genBuiltins {
+				if reflect.ValueOf(fn).Pointer() == reflect.ValueOf(mockFactory[key]).Pointer() {
+					gf.builtin = key
+					break
+				}
+			}
+		}
+		gs.fields[child.GetAlias()] = gf
+		gs.add(m, child, wrapped)
+	}
+}
+
+// covered report whether all the mocked fields are in the aliases
+func (gs *GenStruct) covered(aliases []string) bool {
+	known := make(map[string]struct{}, len(aliases))
+	for _, alias := range aliases {
+		known[alias] = struct{}{}
+	}
+	for alias, gf := range gs.fields {
+		if _, ok := known[alias]; !ok && gf.fl.GetMockFunc() != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// Field return the field by the alias, eg: "Books.0.Id". nil if the field is not mocked
+func (gs *GenStruct) Field(alias string) *GenField {
+	gf := gs.fields[alias]
+	if gf == nil || gf.fl.GetMockFunc() == nil {
+		return nil
+	}
+	return gf
+}
+
+// AfterMock call the hook of the struct, v is the struct ptr, see AfterMocker
+func (gs *GenStruct) AfterMock(ctx context.Context, alias string, v any) error {
+	hook, ok := v.(AfterMocker)
+	if !ok {
+		return nil
+	}
+	err := hook.AfterMock(ctx)
+	if err != nil && alias != "" {
+		return fmt.Errorf("field:%s,err:%v", alias, err)
+	}
+	return err
+}
+
+// direct report whether the built-in mock function is called directly
+func (gf *GenField) direct(key string) bool {
+	return gf.builtin == key
+}
+
+// Set mock the value by reflection, ptr is the ptr of the field. it is used for the types unknown to the generator
+func (gf *GenField) Set(ctx context.Context, ptr any) error {
+	return gf.m.setValue(ctx, reflect.ValueOf(ptr).Elem(), gf.fl, gf.m.mockFunc(gf.fl))
+}
+
+// Len mock the length of the slice
+func (gf *GenField) Len(ctx context.Context) (int, error) {
+	if gf.direct(makeSlice) {
+		return sliceLength(ctx, gf.fl), nil
+	}
+	rv, err := gf.value(ctx)
+	if err != nil || !rv.IsValid() {
+		return 0, err
+	}
+	if rv.Kind() != reflect.Slice {
+		return 0, fmt.Errorf("field:%s,err:not a slice", gf.fl.GetAlias())
+	}
+	return rv.Len(), nil
+}
+
+// value call the mock function wrapped by the middlewares, the ptr is followed
+func (gf *GenField) value(ctx context.Context) (reflect.Value, error) {
+	rv, err := gf.m.mockFunc(gf.fl)(ctx, gf.fl)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	if rv.IsValid() && rv.Kind() == reflect.Pointer {
+		rv = rv.Elem()
+	}
+	return rv, nil
+}
+
+// GenString mock the string of the field
+func GenString[T ~string](ctx context.Context, gf *GenField) (T, error) {
+	if gf.direct(makeString) {
+		val, err := generateString(ctx, gf.fl)
+		return T(val), err
+	}
+	rv, err := gf.value(ctx)
+	if err != nil || !rv.IsValid() {
+		return "", err
+	}
+	if rv.Kind() != reflect.String {
+		return "", fmt.Errorf("field:%s,err:not a string", gf.fl.GetAlias())
+	}
+	return T(rv.String()), nil
+}
+
+// GenInteger mock the integer of the field
+func GenInteger[T ~int | ~int8 | ~int16 | ~int32 | ~int64 | ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64](
+	ctx context.Context, gf *GenField) (T, error) {
+	if gf.direct(makeInteger) {
+		val, err := generateInteger(ctx, gf.fl)
+		return T(val), err
+	}
+	rv, err := gf.value(ctx)
+	if err != nil || !rv.IsValid() {
+		return 0, err
+	}
+	switch {
+	case rv.CanInt():
+		return T(rv.Int()), nil
+	case rv.CanUint():
+		return T(rv.Uint()), nil
+	}
+	return 0, fmt.Errorf("field:%s,err:not an integer", gf.fl.GetAlias())
+}
+
+// GenDecimal mock the decimal of the field
+func GenDecimal[T ~float32 | ~float64](ctx context.Context, gf *GenField) (T, error) {
+	if gf.direct(makeDecimal) {
+		val, err := generateDecimal(ctx, gf.fl)
+		return T(val), err
+	}
+	rv, err := gf.value(ctx)
+	if err != nil || !rv.IsValid() {
+		return 0, err
+	}
+	if !rv.CanFloat() {
+		return 0, fmt.Errorf("field:%s,err:not a decimal", gf.fl.GetAlias())
+	}
+	return T(rv.Float()), nil
+}
+
+// GenNew init the struct ptr of the field
+func GenNew[T any](ctx context.Context, gf *GenField, ptr **T) error {
+	if gf.direct(makeStruct) {
+		*ptr = new(T)
+		return nil
+	}
+	return gf.Set(ctx, ptr)
+}
@@ -0,0 +1,565 @@
+// Package gen generate the typed mock functions of the structs from the go sources, it is used by `gomock gen`.
+// the generated MockUser(m) and MockUserCtx(ctx, m, v) mock the fields with mock tags in the same order by the same
+// mock functions as gomock.StructCtx, without walking the struct by reflection. the fields whose type is unknown
+// to the generator are set by reflection, the nested structs must be declared in the same package.
+// gomock.StructCtx is called instead for the options and the fields unknown to the generator, see gomock.GenCache
+package gen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"io/fs"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultTag    = "mock"
+	tagKeyPattern = ",[a-z_]+="
+	header        = "// Code generated by gomock gen. DO NOT EDIT."
+
+	tagKey  = "key"
+	tagInto = "into"
+	tagSkip = "skip"
+)
+
+var (
+	tagKeyReg = regexp.MustCompile(tagKeyPattern)
+	// the mock keys of the typed primitives
+	stringKeys  = map[string]bool{"string": true, "email": true, "mobile_phone": true, "addr": true}
+	integerKeys = map[string]bool{"integer": true}
+	decimalKeys = map[string]bool{"decimal": true}
+	// the predeclared types
+	stringTypes  = map[string]bool{"string": true}
+	integerTypes = map[string]bool{"int": true, "int8": true, "int16": true, "int32": true, "int64": true,
+		"uint": true, "uint8": true, "uint16": true, "uint32": true, "uint64": true, "byte": true, "rune": true}
+)
+
+// Config of the generator
+type Config struct {
+	Dir    string   //the package directory, default "."
+	Types  []string //the struct names, default all structs with mock tags
+	Output string   //the output file name, it is excluded from the sources
+	Tag    string   //the tag name, default "mock"
+}
+
+// Generate parse the package in the directory and return the formatted source of the mock functions
+func Generate(config Config) ([]byte, error) {
+	if config.Dir == "" {
+		config.Dir = "."
+	}
+	if config.Tag == "" {
+		config.Tag = defaultTag
+	}
+	p, err := parsePackage(config)
+	if err != nil {
+		return nil, err
+	}
+	names := config.Types
+	if len(names) == 0 {
+		names = p.tagged(config.Tag)
+	}
+	if len(names) == 0 {
+		return nil, errors.New("not found the struct with mock tags")
+	}
+	g := &generator{pkg: p, tag: config.Tag, imports: make(map[string]string)}
+	for _, name := range names {
+		if err = g.mockFunc(name); err != nil {
+			return nil, err
+		}
+	}
+	return g.source()
+}
+
+type typeDecl struct {
+	st   *ast.StructType
+	file *ast.File
+}
+
+type pkg struct {
+	name    string
+	fset    *token.FileSet
+	structs map[string]*typeDecl
+	order   []string            //the struct names in the source order
+	hooks   map[string]struct{} //the types with AfterMock method
+}
+
+func parsePackage(config Config) (*pkg, error) {
+	fset := token.NewFileSet()
+	filter := func(info fs.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go") && info.Name() != filepath.Base(config.Output)
+	}
+	pkgs, err := parser.ParseDir(fset, config.Dir, filter, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("expect one package in %s, found %d", config.Dir, len(pkgs))
+	}
+	p := &pkg{fset: fset, structs: make(map[string]*typeDecl), hooks: make(map[string]struct{})}
+	for name, astPkg := range pkgs {
+		p.name = name
+		files := make([]string, 0, len(astPkg.Files))
+		for file := range astPkg.Files {
+			files = append(files, file)
+		}
+		sort.Strings(files)
+		for _, file := range files {
+			p.collect(astPkg.Files[file])
+		}
+	}
+	return p, nil
+}
+
+func (p *pkg) collect(file *ast.File) {
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				ts, ok := spec.(*ast.TypeSpec)
+				if !ok || ts.TypeParams != nil {
+					continue
+				}
+				if st, ok := ts.Type.(*ast.StructType); ok {
+					p.structs[ts.Name.Name] = &typeDecl{st: st, file: file}
+					p.order = append(p.order, ts.Name.Name)
+				}
+			}
+		case *ast.FuncDecl:
+			if decl.Recv == nil || decl.Name.Name != "AfterMock" || len(decl.Recv.List) == 0 {
+				continue
+			}
+			recv := decl.Recv.List[0].Type
+			if star, ok := recv.(*ast.StarExpr); ok {
+				recv = star.X
+			}
+			if ident, ok := recv.(*ast.Ident); ok {
+				p.hooks[ident.Name] = struct{}{}
+			}
+		}
+	}
+}
+
+// tagged return the structs with mock tags
+func (p *pkg) tagged(tag string) []string {
+	var names []string
+	for _, name := range p.order {
+		for _, field := range p.structs[name].st.Fields.List {
+			if fieldTag(field, tag) != "" {
+				names = append(names, name)
+				break
+			}
+		}
+	}
+	return names
+}
+
+type generator struct {
+	pkg     *pkg
+	tag     string
+	buf     bytes.Buffer
+	imports map[string]string //the package name to the import path
+	stack   []string          //the structs being generated, for the recursive types
+	aliases []string          //the fields looked up by the current mock function, they are resolved once
+	depth   int               //the depth of the loops
+	loop    string            //the loop of the base elements, it is opened inside the lookup
+}
+
+func (g *generator) printf(format string, args ...any) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+func (g *generator) source() ([]byte, error) {
+	out := &bytes.Buffer{}
+	fmt.Fprintf(out, "%s\n\npackage %s\n\nimport (\n", header, g.pkg.name)
+	std, others := []string{`"context"`}, []string{`"github.com/pigfu/gomock"`}
+	for name, path := range g.imports {
+		spec := strconv.Quote(path)
+		if name != path[strings.LastIndex(path, "/")+1:] {
+			spec = name + " " + spec
+		}
+		if strings.Contains(strings.Split(path, "/")[0], ".") {
+			others = append(others, spec)
+		} else {
+			std = append(std, spec)
+		}
+	}
+	sort.Strings(std)
+	sort.Strings(others)
+	fmt.Fprintf(out, "%s\n\n%s\n)\n", strings.Join(std, "\n"), strings.Join(others, "\n"))
+	out.Write(g.buf.Bytes())
+	return format.Source(out.Bytes())
+}
+
+func (g *generator) mockFunc(name string) error {
+	if _, ok := g.pkg.structs[name]; !ok {
+		return fmt.Errorf("not found the struct:%s", name)
+	}
+	fn := "Mock" + strings.ToUpper(name[:1]) + name[1:]
+	if !ast.IsExported(name) {
+		fn = "m" + fn[1:]
+	}
+	fields, cache := "gen"+fn+"Fields", "gen"+fn+"Cache"
+	//the body is generated first to collect the fields
+	out := g.buf
+	g.buf, g.aliases = bytes.Buffer{}, nil
+	err := g.structFields(name, "v", "")
+	g.hook(name, "v", "")
+	body := g.buf
+	g.buf = out
+	if err != nil {
+		return fmt.Errorf("struct:%s,%v", name, err)
+	}
+	g.printf("\n// %s is the fields of %s resolved by %s\n", fields, name, cache)
+	g.printf("type %s struct {\ngs *gomock.GenStruct\n", fields)
+	for _, alias := range g.aliases {
+		g.printf("%s *gomock.GenField\n", fieldIdent(alias))
+	}
+	g.printf("}\n\nvar %s = gomock.NewGenCache(func(gs *gomock.GenStruct) *%s {\n", cache, fields)
+	g.printf("return &%s{\ngs: gs,\n", fields)
+	for _, alias := range g.aliases {
+		g.printf("%s: gs.Field(%q),\n", fieldIdent(alias), alias)
+	}
+	g.printf("}\n},\n")
+	for _, alias := range g.aliases {
+		g.printf("%q,\n", alias)
+	}
+	g.printf(")\n")
+	g.printf("\n// %s mock a new %s, see %sCtx\n", fn, name, fn)
+	g.printf("func %s(m *gomock.Mock) (*%s, error) {\n", fn, name)
+	g.printf("v := &%s{}\nreturn v, %sCtx(context.Background(), m, v)\n}\n", name, fn)
+	g.printf("\n// %sCtx mock the fields of %s with mock tags in the same way as m.StructCtx, the fields are resolved once.\n", fn, name)
+	g.printf("// it calls m.StructCtx if ctx has the overrides, WithFillZero or WithTrace, or the fields without mock tags\n")
+	g.printf("// are mocked, eg: by the tag config, the tag sources or the Mocker\n")
+	g.printf("func %sCtx(ctx context.Context, m *gomock.Mock, v *%s) error {\n", fn, name)
+	g.printf("if err := ctx.Err(); err != nil {\nreturn err\n}\n")
+	g.printf("fs, err := %s.Load(ctx, m, v)\nif err != nil {\nreturn err\n}\n", cache)
+	g.printf("if fs == nil {\nreturn m.StructCtx(ctx, v)\n}\n")
+	g.buf.Write(body.Bytes())
+	g.printf("return nil\n}\n")
+	return nil
+}
+
+// lookup record the field and return its name in the resolved fields
+func (g *generator) lookup(alias string) string {
+	for _, known := range g.aliases {
+		if known == alias {
+			return fieldIdent(alias)
+		}
+	}
+	g.aliases = append(g.aliases, alias)
+	return fieldIdent(alias)
+}
+
+// fieldIdent return the name of the resolved field, eg: Books.0.Id -> Books_0_Id
+func fieldIdent(alias string) string {
+	return strings.ReplaceAll(alias, ".", "_")
+}
+
+// structFields generate the fields of the struct, v is the expression of the struct
+func (g *generator) structFields(name, v, alias string) error {
+	for _, parent := range g.stack {
+		if parent == name {
+			return fmt.Errorf("not support the recursive type:%s", name)
+		}
+	}
+	g.stack = append(g.stack, name)
+	defer func() { g.stack = g.stack[:len(g.stack)-1] }()
+	decl := g.pkg.structs[name]
+	for _, field := range decl.st.Fields.List {
+		tag := fieldTag(field, g.tag)
+		if tag == "" {
+			continue
+		}
+		names := field.Names
+		if len(names) == 0 { //embedded
+			names = []*ast.Ident{{Name: typeName(field.Type)}}
+		}
+		for _, ident := range names {
+			if !ast.IsExported(ident.Name) {
+				return fmt.Errorf("field:%s,err:unexported", ident.Name)
+			}
+			err := g.field(decl.file, field.Type, v+"."+ident.Name, joinAlias(alias, ident.Name), splitTag(tag))
+			if err != nil {
+				return fmt.Errorf("field:%s,err:%v", ident.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// field generate the field of the type, v is the expression of the field
+func (g *generator) field(file *ast.File, expr ast.Expr, v, alias string, tags []string) error {
+	own, into, hasInto := splitInto(tags)
+	star, isPtr := expr.(*ast.StarExpr)
+	if isPtr {
+		expr = star.X
+	}
+	if slice, ok := expr.(*ast.ArrayType); ok && !isPtr {
+		if slice.Len != nil {
+			return errors.New("not support the array")
+		}
+		return g.slice(file, slice.Elt, v, alias, own, into, hasInto)
+	}
+	if _, ok := own[tagSkip]; ok {
+		if g.isStruct(expr) && !isPtr {
+			g.hook(typeName(expr), "&"+v, alias)
+		}
+		return nil
+	}
+	if g.isStruct(expr) {
+		return g.structField(expr, isPtr, v, alias, own, hasInto)
+	}
+	if _, ok := expr.(*ast.SelectorExpr); ok && hasInto { //the fields are unknown
+		return fmt.Errorf("not support the struct out of the package:%s", types(g.pkg.fset, expr))
+	}
+	typ, err := g.typeString(file, expr)
+	if err != nil {
+		return err
+	}
+	//the loop of the slice elements is inside the lookup of the element field
+	loop, end := g.loop, ""
+	if g.loop != "" {
+		g.loop, end = "", "}\n"
+	}
+	g.printf("if f := fs.%s; f != nil {\n%s", g.lookup(alias), loop)
+	switch primitive := g.primitive(expr, own[tagKey]); {
+	case primitive == "":
+		g.printf("if err := f.Set(ctx, &%s); err != nil {\nreturn err\n}\n", v)
+	case isPtr:
+		g.printf("x, err := gomock.%s[%s](ctx, f)\nif err != nil {\nreturn err\n}\n%s = &x\n", primitive, typ, v)
+	default:
+		g.printf("x, err := gomock.%s[%s](ctx, f)\nif err != nil {\nreturn err\n}\n%s = x\n", primitive, typ, v)
+	}
+	g.printf("%s}\n", end)
+	return nil
+}
+
+func (g *generator) structField(expr ast.Expr, isPtr bool, v, alias string,
+	own map[string]string, hasInto bool) error {
+	name := typeName(expr)
+	switch _, hasKey := own[tagKey]; {
+	case hasKey:
+		g.printf("if f := fs.%s; f != nil {\nif err := f.Set(ctx, &%s); err != nil {\nreturn err\n}\n}\n", g.lookup(alias), v)
+	case isPtr:
+		g.printf("if f := fs.%s; f != nil {\nif err := gomock.GenNew(ctx, f, &%s); err != nil {\nreturn err\n}\n}\n",
+			g.lookup(alias), v)
+	}
+	if _, ok := g.pkg.structs[name]; !ok { //the anonymous struct
+		return fmt.Errorf("not support the type:%s", types(g.pkg.fset, expr))
+	}
+	ref := "&" + v
+	if isPtr {
+		ref = v
+		g.printf("if %s != nil {\n", v)
+	}
+	if hasInto {
+		if err := g.structFields(name, v, alias); err != nil {
+			return err
+		}
+	}
+	g.hook(name, ref, alias)
+	if isPtr {
+		g.printf("}\n")
+	}
+	return nil
+}
+
+func (g *generator) slice(file *ast.File, elem ast.Expr, v, alias string, own map[string]string,
+	into []string, hasInto bool) error {
+	if _, ok := own[tagSkip]; ok {
+		return nil
+	}
+	typ, err := g.typeString(file, elem)
+	if err != nil {
+		return err
+	}
+	if _, hasKey := own[tagKey]; hasKey {
+		g.printf("if f := fs.%s; f != nil {\nif err := f.Set(ctx, &%s); err != nil {\nreturn err\n}\n}\n", g.lookup(alias), v)
+	} else {
+		g.printf("if f := fs.%s; f != nil {\nn, err := f.Len(ctx)\nif err != nil {\nreturn err\n}\n%s = make([]%s, n)\n}\n",
+			g.lookup(alias), v, typ)
+	}
+	if !hasInto {
+		return nil
+	}
+	inner := elem
+	if star, ok := inner.(*ast.StarExpr); ok {
+		inner = star.X
+	}
+	if _, ok := inner.(*ast.ArrayType); ok {
+		return errors.New("not support the nested slice")
+	}
+	if !g.isStruct(inner) && len(into) == 0 { //the elements are not mocked
+		return nil
+	}
+	if g.isStruct(inner) && len(into) == 0 {
+		into = []string{tagInto + "=1"}
+	}
+	i := fmt.Sprintf("i%d", g.depth)
+	g.depth++
+	defer func() { g.depth-- }()
+	loop := fmt.Sprintf("for %s := range %s {\n", i, v)
+	if !g.isStruct(inner) {
+		g.loop = loop
+		defer func() { g.loop = "" }()
+		return g.field(file, elem, v+"["+i+"]", alias+".0", into)
+	}
+	g.printf(loop)
+	if err = g.field(file, elem, v+"["+i+"]", alias+".0", into); err != nil {
+		return err
+	}
+	g.printf("}\n")
+	return nil
+}
+
+// hook generate the AfterMock call if the struct has the method, ref is the struct ptr
+func (g *generator) hook(name, ref, alias string) {
+	if _, ok := g.pkg.hooks[name]; !ok {
+		return
+	}
+	g.printf("if err := fs.gs.AfterMock(ctx, %q, %s); err != nil {\nreturn err\n}\n", alias, ref)
+}
+
+// primitive return the typed primitive by the mock key and the type, empty for the reflection
+func (g *generator) primitive(expr ast.Expr, key string) string {
+	predeclared := ""
+	if ident, ok := expr.(*ast.Ident); ok {
+		predeclared = ident.Name
+	}
+	switch {
+	case stringKeys[key]:
+		return "GenString"
+	case integerKeys[key]:
+		return "GenInteger"
+	case decimalKeys[key]:
+		return "GenDecimal"
+	case key == "time" && stringTypes[predeclared]:
+		return "GenString"
+	case key == "time" && integerTypes[predeclared]:
+		return "GenInteger"
+	}
+	return ""
+}
+
+func (g *generator) isStruct(expr ast.Expr) bool {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		_, ok := g.pkg.structs[expr.Name]
+		return ok
+	case *ast.StructType:
+		return true
+	}
+	return false
+}
+
+// typeString print the type, the imported packages are recorded
+func (g *generator) typeString(file *ast.File, expr ast.Expr) (string, error) {
+	var err error
+	ast.Inspect(expr, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.StructType, *ast.InterfaceType, *ast.FuncType, *ast.MapType, *ast.ChanType:
+			err = fmt.Errorf("not support the type:%s", types(g.pkg.fset, expr))
+			return false
+		case *ast.SelectorExpr:
+			if x, ok := node.X.(*ast.Ident); ok {
+				if path, ok := importPath(file, x.Name); ok {
+					g.imports[x.Name] = path
+				}
+			}
+			return false
+		}
+		return true
+	})
+	return types(g.pkg.fset, expr), err
+}
+
+func importPath(file *ast.File, name string) (string, bool) {
+	for _, spec := range file.Imports {
+		path, _ := strconv.Unquote(spec.Path.Value)
+		switch {
+		case spec.Name != nil && spec.Name.Name == name:
+			return path, true
+		case spec.Name == nil && (path == name || strings.HasSuffix(path, "/"+name)):
+			return path, true
+		}
+	}
+	return "", false
+}
+
+func types(fset *token.FileSet, expr ast.Expr) string {
+	buf := &bytes.Buffer{}
+	_ = printer.Fprint(buf, fset, expr)
+	return buf.String()
+}
+
+func typeName(expr ast.Expr) string {
+	switch expr := expr.(type) {
+	case *ast.StarExpr:
+		return typeName(expr.X)
+	case *ast.Ident:
+		return expr.Name
+	case *ast.SelectorExpr:
+		return expr.Sel.Name
+	}
+	return ""
+}
+
+func fieldTag(field *ast.Field, name string) string {
+	if field.Tag == nil {
+		return ""
+	}
+	tag, err := strconv.Unquote(field.Tag.Value)
+	if err != nil {
+		return ""
+	}
+	return reflect.StructTag(tag).Get(name)
+}
+
+func joinAlias(alias, name string) string {
+	if alias == "" {
+		return name
+	}
+	return alias + "." + name
+}
+
+// splitTag split the tag as gomock does, the values may contain the separator
+func splitTag(tag string) []string {
+	var (
+		findOut  = tagKeyReg.FindAllString(tag, -1)
+		splitOut = tagKeyReg.Split(tag, -1)
+	)
+	for i := 1; i < len(splitOut); i++ {
+		splitOut[i] = findOut[i-1][1:] + splitOut[i]
+	}
+	return splitOut
+}
+
+// splitInto return the tags of the field and the tags after into for the elements
+func splitInto(tags []string) (map[string]string, []string, bool) {
+	own := make(map[string]string)
+	for i, tag := range tags {
+		kv := strings.SplitN(tag, "=", 2)
+		if len(kv) == 1 {
+			kv = append(kv, "")
+		}
+		own[kv[0]] = kv[1]
+		if kv[0] == tagInto {
+			return own, tags[i+1:], true
+		}
+	}
+	return own, nil, false
+}
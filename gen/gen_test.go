@@ -0,0 +1,45 @@
+package gen
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	src, err := Generate(Config{Dir: "internal/example", Types: []string{"User", "Profile"}, Output: "mock_gen.go"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := os.ReadFile("internal/example/mock_gen.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(src) != string(want) {
+		t.Errorf("mock_gen.go is out of date, run go generate ./gen/...")
+	}
+	if all, err := Generate(Config{Dir: "internal/example", Output: "mock_gen.go"}); err != nil ||
+		!strings.Contains(string(all), "func MockBook(") || !strings.Contains(string(all), "func MockAddress(") {
+		t.Errorf("expect all structs with mock tags, err:%v", err)
+	}
+}
+
+func TestGenerateError(t *testing.T) {
+	for name, src := range map[string]string{
+		"not found the struct:User": "type Order struct{}",
+		"recursive type:Node":       "type User struct{ Next *Node `mock:\"into=1\"` }\ntype Node struct{ Next *Node `mock:\"into=1\"` }",
+		"not support the array":     "type User struct{ Ids [2]int `mock:\"key=integer\"` }",
+		"out of the package":        "import \"time\"\ntype User struct{ At time.Time `mock:\"into=1\"` }",
+		"not support the type":      "type User struct{ Meta map[string]int `mock:\"key=meta\"` }",
+	} {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, "user.go"), []byte("package user\n"+src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		_, err := Generate(Config{Dir: dir, Types: []string{"User"}})
+		if err == nil || !strings.Contains(err.Error(), name) {
+			t.Errorf("expect error %q, got %v", name, err)
+		}
+	}
+}
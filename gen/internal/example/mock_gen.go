@@ -0,0 +1,317 @@
+// Code generated by gomock gen. DO NOT EDIT.
+
+package example
+
+import (
+	"context"
+	"time"
+
+	"github.com/pigfu/gomock"
+)
+
+// genMockUserFields is the fields of User resolved by genMockUserCache
+type genMockUserFields struct {
+	gs             *gomock.GenStruct
+	Id             *gomock.GenField
+	Name           *gomock.GenField
+	Email          *gomock.GenField
+	Level          *gomock.GenField
+	Score          *gomock.GenField
+	Phone          *gomock.GenField
+	Code           *gomock.GenField
+	Tags           *gomock.GenField
+	Tags_0         *gomock.GenField
+	Ids            *gomock.GenField
+	Ids_0          *gomock.GenField
+	Address_City   *gomock.GenField
+	Address_Street *gomock.GenField
+	Backup         *gomock.GenField
+	Backup_City    *gomock.GenField
+	Backup_Street  *gomock.GenField
+	Books          *gomock.GenField
+	Books_0        *gomock.GenField
+	Books_0_Id     *gomock.GenField
+	Books_0_Title  *gomock.GenField
+	Books_0_Price  *gomock.GenField
+	Duration       *gomock.GenField
+}
+
+var genMockUserCache = gomock.NewGenCache(func(gs *gomock.GenStruct) *genMockUserFields {
+	return &genMockUserFields{
+		gs:             gs,
+		Id:             gs.Field("Id"),
+		Name:           gs.Field("Name"),
+		Email:          gs.Field("Email"),
+		Level:          gs.Field("Level"),
+		Score:          gs.Field("Score"),
+		Phone:          gs.Field("Phone"),
+		Code:           gs.Field("Code"),
+		Tags:           gs.Field("Tags"),
+		Tags_0:         gs.Field("Tags.0"),
+		Ids:            gs.Field("Ids"),
+		Ids_0:          gs.Field("Ids.0"),
+		Address_City:   gs.Field("Address.City"),
+		Address_Street: gs.Field("Address.Street"),
+		Backup:         gs.Field("Backup"),
+		Backup_City:    gs.Field("Backup.City"),
+		Backup_Street:  gs.Field("Backup.Street"),
+		Books:          gs.Field("Books"),
+		Books_0:        gs.Field("Books.0"),
+		Books_0_Id:     gs.Field("Books.0.Id"),
+		Books_0_Title:  gs.Field("Books.0.Title"),
+		Books_0_Price:  gs.Field("Books.0.Price"),
+		Duration:       gs.Field("Duration"),
+	}
+},
+	"Id",
+	"Name",
+	"Email",
+	"Level",
+	"Score",
+	"Phone",
+	"Code",
+	"Tags",
+	"Tags.0",
+	"Ids",
+	"Ids.0",
+	"Address.City",
+	"Address.Street",
+	"Backup",
+	"Backup.City",
+	"Backup.Street",
+	"Books",
+	"Books.0",
+	"Books.0.Id",
+	"Books.0.Title",
+	"Books.0.Price",
+	"Duration",
+)
+
+// MockUser mock a new User, see MockUserCtx
+func MockUser(m *gomock.Mock) (*User, error) {
+	v := &User{}
+	return v, MockUserCtx(context.Background(), m, v)
+}
+
+// MockUserCtx mock the fields of User with mock tags in the same way as m.StructCtx, the fields are resolved once.
+// it calls m.StructCtx if ctx has the overrides, WithFillZero or WithTrace, or the fields without mock tags
+// are mocked, eg: by the tag config, the tag sources or the Mocker
+func MockUserCtx(ctx context.Context, m *gomock.Mock, v *User) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	fs, err := genMockUserCache.Load(ctx, m, v)
+	if err != nil {
+		return err
+	}
+	if fs == nil {
+		return m.StructCtx(ctx, v)
+	}
+	if f := fs.Id; f != nil {
+		x, err := gomock.GenInteger[int64](ctx, f)
+		if err != nil {
+			return err
+		}
+		v.Id = x
+	}
+	if f := fs.Name; f != nil {
+		x, err := gomock.GenString[string](ctx, f)
+		if err != nil {
+			return err
+		}
+		v.Name = x
+	}
+	if f := fs.Email; f != nil {
+		x, err := gomock.GenString[string](ctx, f)
+		if err != nil {
+			return err
+		}
+		v.Email = &x
+	}
+	if f := fs.Level; f != nil {
+		x, err := gomock.GenInteger[Level](ctx, f)
+		if err != nil {
+			return err
+		}
+		v.Level = x
+	}
+	if f := fs.Score; f != nil {
+		x, err := gomock.GenDecimal[float64](ctx, f)
+		if err != nil {
+			return err
+		}
+		v.Score = x
+	}
+	if f := fs.Phone; f != nil {
+		x, err := gomock.GenString[string](ctx, f)
+		if err != nil {
+			return err
+		}
+		v.Phone = x
+	}
+	if f := fs.Code; f != nil {
+		if err := f.Set(ctx, &v.Code); err != nil {
+			return err
+		}
+	}
+	if f := fs.Tags; f != nil {
+		n, err := f.Len(ctx)
+		if err != nil {
+			return err
+		}
+		v.Tags = make([]string, n)
+	}
+	if f := fs.Tags_0; f != nil {
+		for i0 := range v.Tags {
+			x, err := gomock.GenString[string](ctx, f)
+			if err != nil {
+				return err
+			}
+			v.Tags[i0] = x
+		}
+	}
+	if f := fs.Ids; f != nil {
+		n, err := f.Len(ctx)
+		if err != nil {
+			return err
+		}
+		v.Ids = make([]*int32, n)
+	}
+	if f := fs.Ids_0; f != nil {
+		for i0 := range v.Ids {
+			x, err := gomock.GenInteger[int32](ctx, f)
+			if err != nil {
+				return err
+			}
+			v.Ids[i0] = &x
+		}
+	}
+	if f := fs.Address_City; f != nil {
+		x, err := gomock.GenString[string](ctx, f)
+		if err != nil {
+			return err
+		}
+		v.Address.City = x
+	}
+	if f := fs.Address_Street; f != nil {
+		x, err := gomock.GenString[string](ctx, f)
+		if err != nil {
+			return err
+		}
+		v.Address.Street = x
+	}
+	if f := fs.Backup; f != nil {
+		if err := gomock.GenNew(ctx, f, &v.Backup); err != nil {
+			return err
+		}
+	}
+	if v.Backup != nil {
+		if f := fs.Backup_City; f != nil {
+			x, err := gomock.GenString[string](ctx, f)
+			if err != nil {
+				return err
+			}
+			v.Backup.City = x
+		}
+		if f := fs.Backup_Street; f != nil {
+			x, err := gomock.GenString[string](ctx, f)
+			if err != nil {
+				return err
+			}
+			v.Backup.Street = x
+		}
+	}
+	if f := fs.Books; f != nil {
+		n, err := f.Len(ctx)
+		if err != nil {
+			return err
+		}
+		v.Books = make([]*Book, n)
+	}
+	for i0 := range v.Books {
+		if f := fs.Books_0; f != nil {
+			if err := gomock.GenNew(ctx, f, &v.Books[i0]); err != nil {
+				return err
+			}
+		}
+		if v.Books[i0] != nil {
+			if f := fs.Books_0_Id; f != nil {
+				x, err := gomock.GenInteger[int32](ctx, f)
+				if err != nil {
+					return err
+				}
+				v.Books[i0].Id = x
+			}
+			if f := fs.Books_0_Title; f != nil {
+				x, err := gomock.GenString[string](ctx, f)
+				if err != nil {
+					return err
+				}
+				v.Books[i0].Title = x
+			}
+			if f := fs.Books_0_Price; f != nil {
+				x, err := gomock.GenDecimal[float32](ctx, f)
+				if err != nil {
+					return err
+				}
+				v.Books[i0].Price = &x
+			}
+			if err := fs.gs.AfterMock(ctx, "Books.0", v.Books[i0]); err != nil {
+				return err
+			}
+		}
+	}
+	if f := fs.Duration; f != nil {
+		x, err := gomock.GenInteger[time.Duration](ctx, f)
+		if err != nil {
+			return err
+		}
+		v.Duration = &x
+	}
+	return nil
+}
+
+// genMockProfileFields is the fields of Profile resolved by genMockProfileCache
+type genMockProfileFields struct {
+	gs *gomock.GenStruct
+	Id *gomock.GenField
+}
+
+var genMockProfileCache = gomock.NewGenCache(func(gs *gomock.GenStruct) *genMockProfileFields {
+	return &genMockProfileFields{
+		gs: gs,
+		Id: gs.Field("Id"),
+	}
+},
+	"Id",
+)
+
+// MockProfile mock a new Profile, see MockProfileCtx
+func MockProfile(m *gomock.Mock) (*Profile, error) {
+	v := &Profile{}
+	return v, MockProfileCtx(context.Background(), m, v)
+}
+
+// MockProfileCtx mock the fields of Profile with mock tags in the same way as m.StructCtx, the fields are resolved once.
+// it calls m.StructCtx if ctx has the overrides, WithFillZero or WithTrace, or the fields without mock tags
+// are mocked, eg: by the tag config, the tag sources or the Mocker
+func MockProfileCtx(ctx context.Context, m *gomock.Mock, v *Profile) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	fs, err := genMockProfileCache.Load(ctx, m, v)
+	if err != nil {
+		return err
+	}
+	if fs == nil {
+		return m.StructCtx(ctx, v)
+	}
+	if f := fs.Id; f != nil {
+		x, err := gomock.GenInteger[int64](ctx, f)
+		if err != nil {
+			return err
+		}
+		v.Id = x
+	}
+	return nil
+}
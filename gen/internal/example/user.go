@@ -0,0 +1,62 @@
+// Package example is the fixture of the generator, mock_gen.go is generated by `gomock gen`.
+package example
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pigfu/gomock"
+)
+
+//go:generate go run github.com/pigfu/gomock/cmd/gomock gen -type User,Profile
+
+type Level int8
+
+type User struct {
+	Id       int64          `mock:"key=integer,gte=1,lte=1000"`
+	Name     string         `mock:"key=string,gte=3,lte=8"`
+	Email    *string        `mock:"key=email"`
+	Level    Level          `mock:"key=integer,options=1 2 3,weights=1 2 7"`
+	Score    float64        `mock:"key=decimal,gte=0,lte=100.00"`
+	Phone    string         `mock:"key=mobile_phone"`
+	Code     string         `mock:"key=code"` //registered by the test
+	Tags     []string       `mock:"lte=3,into=1,key=string,reg=[a-z]{4}"`
+	Ids      []*int32       `mock:"eq=2,into=1,key=integer,gte=-5,lt=5"`
+	Address  Address        `mock:"into=1"`
+	Backup   *Address       `mock:"into=1"`
+	Books    []*Book        `mock:"gte=1,lte=3,into=1"`
+	Secret   string         `mock:"skip=1"`
+	Duration *time.Duration `mock:"key=integer,gte=1,lte=60"`
+	Note     string
+}
+
+type Address struct {
+	City   string `mock:"key=string,options=beijing shanghai"`
+	Street string `mock:"key=string,gte=5,lte=10"`
+}
+
+type Book struct {
+	Id    int32    `mock:"key=integer,gte=1,lte=100"`
+	Title string   `mock:"key=string,gte=1,lte=5"`
+	Price *float32 `mock:"key=decimal,gte=1.0,lte=99.9"`
+}
+
+func (b *Book) AfterMock(_ context.Context) error {
+	b.Title = strings.ToUpper(b.Title)
+	return nil
+}
+
+// Profile has the fields mocked without mock tags, the generated code calls StructCtx for it
+type Profile struct {
+	Id     int64  `mock:"key=integer,gte=1,lte=100"`
+	Nick   string `binding:"required,min=2,max=5"` //by the validator tag source
+	Status Status
+}
+
+type Status string
+
+func (s *Status) MockValue(ctx context.Context, _ gomock.FieldLevel) error {
+	*s = Status([]string{"active", "banned"}[gomock.GetRand(ctx).Intn(2)])
+	return nil
+}
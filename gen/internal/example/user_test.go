@@ -0,0 +1,161 @@
+package example
+
+import (
+	"context"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pigfu/gomock"
+)
+
+func newMock(t *testing.T) *gomock.Mock {
+	m := gomock.New()
+	err := m.RegisterMock("code", func(ctx context.Context, fl gomock.FieldLevel) (reflect.Value, error) {
+		return reflect.ValueOf("C" + string(rune('A'+gomock.GetRand(ctx).Intn(26)))), nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestEquivalence(t *testing.T) {
+	m := newMock(t)
+	upper := func(next gomock.MockFunc) gomock.MockFunc {
+		return func(ctx context.Context, fl gomock.FieldLevel) (reflect.Value, error) {
+			rv, err := next(ctx, fl)
+			if err == nil && fl.GetName() == "Street" {
+				rv = reflect.ValueOf(strings.ToUpper(rv.String()))
+			}
+			return rv, err
+		}
+	}
+	for _, tc := range []struct {
+		name string
+		ctx  func(seed int64) context.Context
+	}{
+		{"seed", func(seed int64) context.Context {
+			return gomock.WithRand(context.Background(), rand.New(rand.NewSource(seed)))
+		}},
+		{"boundary", func(seed int64) context.Context {
+			return gomock.WithBoundary(gomock.WithRand(context.Background(), rand.New(rand.NewSource(seed))))
+		}},
+	} {
+		for seed := int64(0); seed < 50; seed++ {
+			want, got := &User{}, &User{}
+			if err := m.StructCtx(tc.ctx(seed), want); err != nil {
+				t.Fatal(err)
+			}
+			if err := MockUserCtx(tc.ctx(seed), m, got); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(want, got) {
+				t.Fatalf("%s %d: expect the same user\n%+v\n%+v", tc.name, seed, want, got)
+			}
+		}
+	}
+
+	//the options of ctx unknown to the generated code are mocked by StructCtx
+	tr := gomock.NewTrace()
+	for name, ctx := range map[string]func(seed int64) context.Context{
+		"override": func(seed int64) context.Context {
+			return gomock.WithOverride(gomock.WithRand(context.Background(), rand.New(rand.NewSource(seed))), "Name", "fixed")
+		},
+		"fill zero": func(seed int64) context.Context {
+			return gomock.WithFillZero(gomock.WithRand(context.Background(), rand.New(rand.NewSource(seed))))
+		},
+		"trace": func(seed int64) context.Context {
+			return gomock.WithTrace(gomock.WithRand(context.Background(), rand.New(rand.NewSource(seed))), tr)
+		},
+	} {
+		want, got := &User{Name: "kept"}, &User{Name: "kept"}
+		if err := m.StructCtx(ctx(1), want); err != nil {
+			t.Fatal(err)
+		}
+		if err := MockUserCtx(ctx(1), m, got); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(want, got) {
+			t.Fatalf("%s: expect the same user\n%+v\n%+v", name, want, got)
+		}
+	}
+	if got := MockUserCtx(context.Background(), m, &User{}); got != nil || len(tr.Entries()) == 0 {
+		t.Fatalf("expect the trace recorded, err:%v", got)
+	}
+
+	//the fields without mock tags given by the tag config, the tag sources or the Mocker
+	ruled := newMock(t)
+	sameUser := func(seed int64) *User {
+		want, got := &User{}, &User{}
+		_ = ruled.StructCtx(gomock.WithRand(context.Background(), rand.New(rand.NewSource(seed))), want)
+		err := MockUserCtx(gomock.WithRand(context.Background(), rand.New(rand.NewSource(seed))), ruled, got)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(want, got) {
+			t.Fatalf("%d: expect the same user with the rules\n%+v\n%+v", seed, want, got)
+		}
+		return got
+	}
+	if err := ruled.For(&User{}).Field("Name").Len(9, 9).Err(); err != nil {
+		t.Fatal(err)
+	}
+	if got := sameUser(1); len(got.Name) != 9 || got.Note != "" { //the tagged field is still generated
+		t.Fatalf("expect the rule of the builder: %+v", got)
+	}
+	ruled.RegisterTagConfig(map[string]string{
+		"github.com/pigfu/gomock/gen/internal/example.User.Note": "key=string,gte=2,lte=4",
+	}, gomock.TagConfigMerge)
+	ruled.RegisterTagSource(gomock.ValidatorTagSource("binding"))
+	for seed := int64(0); seed < 20; seed++ {
+		ctx := func() context.Context {
+			return gomock.WithRand(context.Background(), rand.New(rand.NewSource(seed)))
+		}
+		if got := sameUser(seed); len(got.Note) < 2 || len(got.Name) != 9 {
+			t.Fatalf("expect the rules of the untagged field: %+v", got)
+		}
+		wantProfile, gotProfile := &Profile{}, &Profile{}
+		_ = ruled.StructCtx(ctx(), wantProfile)
+		if err := MockProfileCtx(ctx(), ruled, gotProfile); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(wantProfile, gotProfile) || len(gotProfile.Nick) < 2 || gotProfile.Status == "" {
+			t.Fatalf("%d: expect the same profile\n%+v\n%+v", seed, wantProfile, gotProfile)
+		}
+	}
+
+	m.Use(upper) //the middlewares are called by the generated code too
+	want, got := &User{}, &User{}
+	_ = m.StructCtx(gomock.WithRand(context.Background(), rand.New(rand.NewSource(7))), want)
+	if err := MockUserCtx(gomock.WithRand(context.Background(), rand.New(rand.NewSource(7))), m, got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(want, got) || got.Address.Street != strings.ToUpper(got.Address.Street) {
+		t.Fatalf("expect the same user with the middleware\n%+v\n%+v", want, got)
+	}
+	for _, book := range got.Books {
+		if book.Title != strings.ToUpper(book.Title) {
+			t.Errorf("expect the hook called: %s", book.Title)
+		}
+	}
+}
+
+func BenchmarkStruct(b *testing.B) {
+	m := gomock.New()
+	_ = m.RegisterMock("code", func(ctx context.Context, fl gomock.FieldLevel) (reflect.Value, error) {
+		return reflect.ValueOf("C"), nil
+	})
+	ctx := gomock.WithRand(context.Background(), rand.New(rand.NewSource(1)))
+	b.Run("reflect", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_ = m.StructCtx(ctx, &User{})
+		}
+	})
+	b.Run("generated", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_ = MockUserCtx(ctx, m, &User{})
+		}
+	})
+}
@@ -0,0 +1,3 @@
+// Package sqlitetest runs the sql seeding against a real in-process SQLite, it is a separate module so the driver
+// stays out of gomock. run it by `make check` in the repository root.
+package sqlitetest
@@ -0,0 +1,103 @@
+package sqlitetest
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pigfu/gomock"
+	_ "modernc.org/sqlite"
+)
+
+type Status string
+
+func (s Status) Value() (driver.Value, error) {
+	return strings.ToUpper(string(s)), nil
+}
+
+type Base struct {
+	ID        int64  `db:"id" mock:"key=integer,gte=1,lte=1000000"`
+	CreatedAt string `mock:"key=time,time=2006-01-02"`
+}
+
+type User struct {
+	Base   `mock:"into=1"`
+	Name   string  `gorm:"column:user_name" mock:"key=string,options=O'Brien"`
+	Path   string  `mock:"key=string,options=a\\b"`
+	Email  *string `mock:"key=email"`
+	Score  float64 `mock:"key=decimal,gte=0,lte=100"`
+	Status Status  `mock:"key=string,options=on off"`
+	Note   string
+}
+
+const schema = `(id INTEGER, created_at TEXT, user_name TEXT, path TEXT, email TEXT, score REAL, status TEXT)`
+
+func TestSQLite(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1) //every connection has its own memory database
+	ctx := context.Background()
+	for _, table := range []string{"exec_users", "script_users"} {
+		if _, err = db.ExecContext(ctx, "CREATE TABLE "+table+" "+schema); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	//the same seed gives the same rows through the placeholders and the literals
+	mock := gomock.New()
+	seed := func() context.Context {
+		return gomock.WithRand(ctx, rand.New(rand.NewSource(7)))
+	}
+	config := gomock.SQLConfig{Dialect: gomock.SQLite, Table: "exec_users", Batch: 4}
+	if err = gomock.ExecInsert[User](seed(), mock, db, 10, config); err != nil {
+		t.Fatal(err)
+	}
+	script := &strings.Builder{}
+	config.Table = "script_users"
+	if err = gomock.WriteInsert[User](seed(), mock, script, 10, config); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = db.ExecContext(ctx, script.String()); err != nil {
+		t.Fatalf("%v:\n%s", err, script)
+	}
+
+	execRows, scriptRows := readUsers(t, db, "exec_users"), readUsers(t, db, "script_users")
+	if len(execRows) != 10 || !reflect.DeepEqual(execRows, scriptRows) {
+		t.Fatalf("expect the same rows\n%v\n%v", execRows, scriptRows)
+	}
+	for _, row := range execRows {
+		if row.ID < 1 || row.ID > 1000000 || row.Name != "O'Brien" || row.Path != `a\b` ||
+			!strings.Contains(*row.Email, "@") || row.Score < 0 || row.Score > 100 ||
+			row.Status != "ON" && row.Status != "OFF" || len(row.CreatedAt) != len("2006-01-02") {
+			t.Errorf("unexpected row: %+v", row)
+		}
+	}
+}
+
+func readUsers(t *testing.T, db *sql.DB, table string) []User {
+	rows, err := db.Query("SELECT id, created_at, user_name, path, email, score, status FROM " + table + " ORDER BY rowid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	var users []User
+	for rows.Next() {
+		var user User
+		err = rows.Scan(&user.ID, &user.CreatedAt, &user.Name, &user.Path, &user.Email, &user.Score, &user.Status)
+		if err != nil {
+			t.Fatal(err)
+		}
+		users = append(users, user)
+	}
+	if err = rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return users
+}
@@ -0,0 +1,301 @@
+package gomock
+
+import (
+	"context"
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+)
+
+// the max length of the string or slice made too long
+const maxInvalidLen = 1 << 16
+
+// violation change one field to violate one of its mock tags
+type violation struct {
+	val  reflect.Value //the field value as declared
+	fl   FieldLevel
+	path string
+	make func() (reflect.Value, error) //make the invalid value of fl.GetType()
+}
+
+// Invalid mock the struct and change one of its fields randomly to violate exactly one mock tag,
+// eg: the string is too long, the integer is out of range, the string does not match reg or the value is not in options.
+// the returned FieldError tells which field and tag is violated, it is the same as the error of Validate
+func (m *Mock) Invalid(s any) (*FieldError, error) {
+	return m.InvalidCtx(context.Background(), s)
+}
+func (m *Mock) InvalidCtx(ctx context.Context, s any) (*FieldError, error) {
+	if err := m.StructCtx(ctx, s); err != nil {
+		return nil, err
+	}
+	val := reflect.ValueOf(s)
+	fl, err := m.genCache(ctx, val)
+	if err != nil {
+		return nil, err
+	}
+	var violations []violation
+	err = m.walkValue(val, fl, "", func(val reflect.Value, fl FieldLevel, path string) error {
+		violations = append(violations, m.violations(ctx, val, fl, path)...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, i := range GetRand(ctx).Perm(len(violations)) {
+		fe, err := m.violate(ctx, s, violations[i])
+		if err != nil || fe != nil {
+			return fe, err
+		}
+	}
+	return nil, errors.New("no mock tag can be violated")
+}
+
+// violate set the invalid value, it is reverted unless exactly the field is invalid
+func (m *Mock) violate(ctx context.Context, s any, v violation) (*FieldError, error) {
+	rv, err := v.make()
+	if err != nil {
+		return nil, err
+	}
+	old := reflect.New(v.val.Type()).Elem()
+	old.Set(v.val)
+	if v.fl.IsPtr() { //do not change the value shared by the ptr
+		ptr := reflect.New(v.fl.GetType())
+		ptr.Elem().Set(rv)
+		rv = ptr
+	}
+	v.val.Set(rv)
+	var ve ValidationErrors
+	if err = m.ValidateCtx(ctx, s); !errors.As(err, &ve) || len(ve) != 1 || ve[0].Path != v.path {
+		v.val.Set(old)
+		return nil, nil
+	}
+	return ve[0], nil
+}
+
+// violations return the ways to violate the rule that the field follows, see validateField
+func (m *Mock) violations(ctx context.Context, val reflect.Value, fl FieldLevel, path string) []violation {
+	tm := fl.GetTags()
+	if len(tm) == 0 || tm.Key(MockSkip).Exists() || fl.IsPtr() && val.IsNil() {
+		return nil
+	}
+	value := val
+	if fl.IsPtr() {
+		value = val.Elem()
+	}
+	var makes []func() (reflect.Value, error)
+	switch {
+	case fl.GetKind() == reflect.String:
+		makes = invalidStrings(tm, fl.GetType(), value.String())
+	case isIntegerKind(fl.GetType()):
+		makes = invalidIntegers(tm, fl.GetType())
+	case isDecimalKind(fl.GetType()):
+		makes = invalidDecimals(tm, fl.GetType())
+	case fl.GetKind() == reflect.Slice:
+		makes = m.invalidSlices(ctx, tm, fl, value)
+	}
+	violations := make([]violation, 0, len(makes))
+	for _, fn := range makes {
+		violations = append(violations, violation{val: val, fl: fl, path: path, make: fn})
+	}
+	return violations
+}
+
+func invalidStrings(tm TagLevelMap, rt reflect.Type, value string) []func() (reflect.Value, error) {
+	if eqVal := tm.Key(MockEqual).GetStr(); eqVal != "" {
+		return []func() (reflect.Value, error){stringOf(rt, eqVal+"x")}
+	}
+	if tm.Key(MockOptions).Exists() {
+		options, option := tm.Key(MockOptions).GetStrSet(), "x"
+		for contains(options, option) {
+			option += "x"
+		}
+		return []func() (reflect.Value, error){stringOf(rt, option)}
+	}
+	if tm.Key(MockRegExp).Exists() {
+		for _, candidate := range []string{"", " ", value + " ", "~!@#"} {
+			if !matchRegExp(tm.Key(MockRegExp).GetStr(), candidate) {
+				return []func() (reflect.Value, error){stringOf(rt, candidate)}
+			}
+		}
+		return nil
+	}
+	var makes []func() (reflect.Value, error)
+	for _, n := range invalidLengths(tm) {
+		makes = append(makes, stringOf(rt, strings.Repeat("x", n)))
+	}
+	return makes
+}
+
+func stringOf(rt reflect.Type, value string) func() (reflect.Value, error) {
+	return func() (reflect.Value, error) {
+		rv := reflect.New(rt).Elem()
+		rv.SetString(value)
+		return rv, nil
+	}
+}
+
+// invalidLengths return the length too short or too long
+func invalidLengths(tm TagLevelMap) []int {
+	var lengths []int
+	if tm.Key(MockGt).Exists() && tm.Key(MockGt).GetInt() >= 0 {
+		lengths = append(lengths, tm.Key(MockGt).GetInt())
+	}
+	if tm.Key(MockGte).Exists() && tm.Key(MockGte).GetInt() > 0 {
+		lengths = append(lengths, tm.Key(MockGte).GetInt()-1)
+	}
+	if tm.Key(MockLt).Exists() && tm.Key(MockLt).GetInt() >= 0 {
+		lengths = append(lengths, tm.Key(MockLt).GetInt())
+	}
+	if tm.Key(MockLte).Exists() && tm.Key(MockLte).GetInt() >= 0 {
+		lengths = append(lengths, tm.Key(MockLte).GetInt()+1)
+	}
+	values := lengths[:0]
+	for _, n := range lengths {
+		if n <= maxInvalidLen {
+			values = append(values, n)
+		}
+	}
+	return values
+}
+
+func invalidIntegers(tm TagLevelMap, rt reflect.Type) []func() (reflect.Value, error) {
+	var values []int64
+	switch {
+	case tm.Key(MockEqual).Exists():
+		values = appendInt(values, tm.Key(MockEqual).GetInt64(), 1)
+		values = appendInt(values, tm.Key(MockEqual).GetInt64(), -1)
+	case tm.Key(MockOptions).Exists():
+		options := tm.Key(MockOptions).GetInt64Set()
+		values = appendInt(values, sliceMax(options), 1)
+		values = appendInt(values, sliceMin(options), -1)
+	case tm.Key(MockRegExp).Exists(): //can not be validated, see validateInteger
+	default:
+		if tm.Key(MockGt).Exists() {
+			values = append(values, tm.Key(MockGt).GetInt64())
+		}
+		if tm.Key(MockGte).Exists() {
+			values = appendInt(values, tm.Key(MockGte).GetInt64(), -1)
+		}
+		if tm.Key(MockLt).Exists() {
+			values = append(values, tm.Key(MockLt).GetInt64())
+		}
+		if tm.Key(MockLte).Exists() {
+			values = appendInt(values, tm.Key(MockLte).GetInt64(), 1)
+		}
+	}
+	var makes []func() (reflect.Value, error)
+	for _, value := range values { //the values out of the kind range are skipped, eg: eq=127 on int8
+		rv := reflect.New(rt).Elem()
+		switch {
+		case rv.CanInt() && !rv.OverflowInt(value):
+			rv.SetInt(value)
+		case rv.CanUint() && value >= 0 && !rv.OverflowUint(uint64(value)):
+			rv.SetUint(uint64(value))
+		default:
+			continue
+		}
+		makes = append(makes, func() (reflect.Value, error) { return rv, nil })
+	}
+	return makes
+}
+
+// appendInt append v+delta unless it leaves the int64 range
+func appendInt(values []int64, v, delta int64) []int64 {
+	if delta > 0 && v > math.MaxInt64-delta || delta < 0 && v < math.MinInt64-delta {
+		return values
+	}
+	return append(values, v+delta)
+}
+
+func invalidDecimals(tm TagLevelMap, rt reflect.Type) []func() (reflect.Value, error) {
+	var (
+		values []float64
+		step   = 1 / decimalConversion(tm)
+	)
+	switch {
+	case tm.Key(MockEqual).Exists():
+		values = []float64{tm.Key(MockEqual).GetFloat64() + 1, tm.Key(MockEqual).GetFloat64() - 1}
+	case tm.Key(MockOptions).Exists():
+		options := tm.Key(MockOptions).GetFloat64Set()
+		values = []float64{sliceMax(options) + 1, sliceMin(options) - 1}
+	case tm.Key(MockRegExp).Exists(): //can not be validated, see validateDecimal
+	default:
+		if tm.Key(MockGt).Exists() {
+			values = append(values, tm.Key(MockGt).GetFloat64())
+		}
+		if tm.Key(MockGte).Exists() {
+			values = append(values, tm.Key(MockGte).GetFloat64()-step)
+		}
+		if tm.Key(MockLt).Exists() {
+			values = append(values, tm.Key(MockLt).GetFloat64())
+		}
+		if tm.Key(MockLte).Exists() {
+			values = append(values, tm.Key(MockLte).GetFloat64()+step)
+		}
+	}
+	var makes []func() (reflect.Value, error)
+	for _, value := range values {
+		rv := reflect.New(rt).Elem()
+		if rv.OverflowFloat(value) {
+			continue
+		}
+		rv.SetFloat(value)
+		makes = append(makes, func() (reflect.Value, error) { return rv, nil })
+	}
+	return makes
+}
+
+// invalidSlices change the length of the slice, the new elements are mocked so that they are valid
+func (m *Mock) invalidSlices(ctx context.Context, tm TagLevelMap, fl FieldLevel, val reflect.Value) []func() (reflect.Value, error) {
+	var lengths []int
+	if eq := tm.Key(MockEqual).GetInt(); eq > 0 {
+		lengths = []int{eq - 1, eq + 1}
+	} else {
+		lengths = invalidLengths(tm)
+	}
+	makes := make([]func() (reflect.Value, error), 0, len(lengths))
+	for _, n := range lengths {
+		n := n
+		makes = append(makes, func() (reflect.Value, error) {
+			rv := reflect.MakeSlice(fl.GetType(), n, n)
+			reflect.Copy(rv, val)
+			if len(fl.GetChildren()) == 0 { //not into
+				return rv, nil
+			}
+			var (
+				err  error
+				elem = fl.GetChildren()[0]
+			)
+			for i := val.Len(); i < n && err == nil; i++ {
+				if rt, _ := m.Indirect(rv.Index(i).Type()); rt.Kind() == reflect.Struct {
+					err = m.mockStructValue(ctx, rv.Index(i), elem)
+				} else {
+					err = m.mockValue(ctx, rv.Index(i), elem)
+				}
+			}
+			return rv, err
+		})
+	}
+	return makes
+}
+
+func sliceMax[T int64 | float64](values []T) T {
+	var value T
+	for i, v := range values {
+		if i == 0 || v > value {
+			value = v
+		}
+	}
+	return value
+}
+
+func sliceMin[T int64 | float64](values []T) T {
+	var value T
+	for i, v := range values {
+		if i == 0 || v < value {
+			value = v
+		}
+	}
+	return value
+}
@@ -0,0 +1,85 @@
+package gomock
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"reflect"
+	"sync"
+)
+
+// MakeN mock n values of T, T is a struct or a struct ptr, the negative n is an error
+func MakeN[T any](ctx context.Context, m *Mock, n int) ([]T, error) {
+	if n < 0 {
+		return nil, errors.New("negative n")
+	}
+	values := make([]T, 0, n)
+	for i := 0; i < n; i++ {
+		value, err := makeOne[T](ctx, m)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// MakeParallel mock n values of T by the workers, the value i is mocked by a random source seeded with seed+i,
+// so the output is the same for the same seed regardless of the number of workers
+func MakeParallel[T any](ctx context.Context, m *Mock, n, workers int, seed int64) ([]T, error) {
+	if n < 0 {
+		return nil, errors.New("negative n")
+	}
+	if workers <= 0 {
+		workers = 1
+	}
+	var (
+		values = make([]T, n)
+		wg     = &sync.WaitGroup{}
+		once   = &sync.Once{}
+		err    error
+	)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			r := rand.New(rand.NewSource(seed)) //per worker
+			wctx := WithRand(ctx, r)
+			for i := w; i < n && ctx.Err() == nil; i += workers {
+				r.Seed(seed + int64(i))
+				value, e := makeOne[T](wctx, m)
+				if e != nil {
+					once.Do(func() {
+						err = e
+						cancel()
+					})
+					return
+				}
+				values[i] = value
+			}
+		}(w)
+	}
+	wg.Wait()
+	if err != nil {
+		return nil, err
+	}
+	if e := ctx.Err(); e != nil {
+		return nil, e
+	}
+	return values, nil
+}
+
+func makeOne[T any](ctx context.Context, m *Mock) (T, error) {
+	var value T
+	rv := reflect.ValueOf(&value).Elem()
+	switch {
+	case rv.Kind() == reflect.Struct:
+		return value, m.StructCtx(ctx, &value)
+	case rv.Kind() == reflect.Pointer && rv.Type().Elem().Kind() == reflect.Struct:
+		rv.Set(reflect.New(rv.Type().Elem()))
+		return value, m.StructCtx(ctx, value)
+	}
+	return value, errors.New("not a struct or struct ptr")
+}
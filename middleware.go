@@ -0,0 +1,79 @@
+package gomock
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// Middleware wrap the mock functions of the fields, eg: trim to the column width, mask PII.
+// the overrides given by WithOverride are not wrapped.
+type Middleware func(next MockFunc) MockFunc
+
+// AfterMocker is implemented by the struct which needs post-processing, AfterMock is called once its children are mocked
+type AfterMocker interface {
+	AfterMock(ctx context.Context) error
+}
+
+// Use add the middlewares, the first one is the outermost. the mock functions are wrapped once per field
+// when the struct is parsed, so the cached trees are invalidated
+func (m *Mock) Use(mws ...Middleware) {
+	m.Lock()
+	defer m.Unlock()
+	var middlewares []Middleware
+	if prev := m.middlewares.Load(); prev != nil {
+		middlewares = append(middlewares, *prev...)
+	}
+	for _, mw := range mws {
+		if mw != nil {
+			middlewares = append(middlewares, mw)
+		}
+	}
+	m.middlewares.Store(&middlewares)
+	m.cache.reset()
+}
+
+// mockFunc return the mock function of the field wrapped by the middlewares
+func (m *Mock) mockFunc(fl FieldLevel) MockFunc {
+	if mf, ok := fl.(*mockField); ok {
+		return mf.wrapped
+	}
+	return m.wrap(fl.GetMockFunc())
+}
+
+// wrapTree wrap the mock functions of the tree by the middlewares
+func (m *Mock) wrapTree(mf *mockField) {
+	mf.wrapped = m.wrap(mf.mf)
+	for _, child := range mf.children {
+		m.wrapTree(child.(*mockField))
+	}
+}
+
+func (m *Mock) wrap(fn MockFunc) MockFunc {
+	middlewares := m.middlewares.Load()
+	if fn == nil || middlewares == nil {
+		return fn
+	}
+	for i := len(*middlewares) - 1; i >= 0; i-- {
+		fn = (*middlewares)[i](fn)
+	}
+	return fn
+}
+
+// afterMock call the hook of the struct, val is the struct value
+func afterMock(ctx context.Context, val reflect.Value, fl FieldLevel) error {
+	var hook AfterMocker
+	if val.CanAddr() {
+		hook, _ = val.Addr().Interface().(AfterMocker)
+	} else {
+		hook, _ = val.Interface().(AfterMocker)
+	}
+	if hook == nil {
+		return nil
+	}
+	err := hook.AfterMock(ctx)
+	if err != nil && fl.GetAlias() != "" {
+		return fmt.Errorf("field:%s,err:%v", fl.GetAlias(), err)
+	}
+	return err
+}
@@ -5,7 +5,9 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"strings"
 	"sync"
+	"sync/atomic"
 )
 
 const (
@@ -17,6 +19,9 @@ const (
 	maxTagKey           = 99
 )
 
+// Mock is safe for concurrent use. the parsed field trees are cached by type and the cache hits are lock-free,
+// the registrations are serialized with the parsing and take effect on the types parsed after them.
+// the random source of a call is given by WithRand, it must not be shared between concurrent calls.
 type Mock struct {
 	*sync.Mutex
 	tag          string //mock tag mark
@@ -25,6 +30,10 @@ type Mock struct {
 	cache        *cache
 	mockFactory  map[string]MockFunc
 	tagFactory   map[string]TagFunc
+	tagSources   []TagSource
+	tagConfig    map[string]tagRule
+	middlewares  atomic.Pointer[[]Middleware]
+	genStructs   sync.Map //the prepared structs of the generated code, see GenStruct
 }
 
 func New() *Mock {
@@ -46,30 +55,98 @@ func New() *Mock {
 	return mock
 }
 
-// RegisterMock register mock function by yourself
-func (m *Mock) RegisterMock(key string, mf MockFunc) {
+// RegisterMock register mock function by yourself, the existing key is not replaced, see ReplaceMock
+func (m *Mock) RegisterMock(key string, mf MockFunc) error {
 	if mf == nil {
-		return
+		return errors.New("nil mock function")
 	}
 	m.Lock()
 	defer m.Unlock()
 	if _, ok := m.mockFactory[key]; ok {
-		return
+		return fmt.Errorf("mock key:%s already exists", key)
 	}
 	m.mockFactory[key] = mf
+	return nil
+}
+
+// ReplaceMock register or replace the mock function, include the built-in
+func (m *Mock) ReplaceMock(key string, mf MockFunc) error {
+	if mf == nil {
+		return errors.New("nil mock function")
+	}
+	m.Lock()
+	defer m.Unlock()
+	m.mockFactory[key] = mf
+	m.cache.invalidate(func(mf *mockField) bool {
+		_, ok := mf.mockKeys[key]
+		return ok
+	})
+	return nil
 }
 
-// RegisterTag register tag parse function by yourself
-func (m *Mock) RegisterTag(key string, mt TagFunc) {
+// UnregisterMock remove the mock function, the fields using it can not be mocked any more
+func (m *Mock) UnregisterMock(key string) error {
+	if key == makeSlice || key == makeStruct {
+		return fmt.Errorf("mock key:%s is required", key)
+	}
+	m.Lock()
+	defer m.Unlock()
+	if _, ok := m.mockFactory[key]; !ok {
+		return fmt.Errorf("not found mock key type:%s", key)
+	}
+	delete(m.mockFactory, key)
+	m.cache.invalidate(func(mf *mockField) bool {
+		_, ok := mf.mockKeys[key]
+		return ok
+	})
+	return nil
+}
+
+// RegisterTag register tag parse function by yourself, the existing key is not replaced, see ReplaceTag
+func (m *Mock) RegisterTag(key string, mt TagFunc) error {
 	if mt == nil {
-		return
+		return errors.New("nil tag function")
 	}
 	m.Lock()
 	defer m.Unlock()
 	if _, ok := m.tagFactory[key]; ok {
-		return
+		return fmt.Errorf("mock tag:%s already exists", key)
 	}
 	m.tagFactory[key] = mt
+	return nil
+}
+
+// ReplaceTag register or replace the tag parse function, include the built-in
+func (m *Mock) ReplaceTag(key string, mt TagFunc) error {
+	if mt == nil {
+		return errors.New("nil tag function")
+	}
+	m.Lock()
+	defer m.Unlock()
+	m.tagFactory[key] = mt
+	m.cache.invalidate(func(mf *mockField) bool {
+		_, ok := mf.tagKeys[key]
+		return ok
+	})
+	return nil
+}
+
+// UnregisterTag remove the tag parse function, the fields using it can not be mocked any more
+func (m *Mock) UnregisterTag(key string) error {
+	if key == MockKey || key == MockInto {
+		return fmt.Errorf("mock tag:%s is required", key)
+	}
+	m.Lock()
+	defer m.Unlock()
+	if _, ok := m.tagFactory[key]; !ok {
+		return fmt.Errorf("not support the mock tag:%s", key)
+	}
+	delete(m.tagFactory, key)
+	m.cache.invalidate(func(mf *mockField) bool {
+		_, ok := mf.tagKeys[key]
+		return ok
+	})
+	return nil
 }
 func (m *Mock) Struct(s any) error {
 	return m.StructCtx(context.Background(), s)
@@ -82,6 +159,20 @@ func (m *Mock) StructCtx(ctx context.Context, s any) (err error) {
 	return m.mockStruct(ctx, val, nil)
 }
 
+type fillZeroKey struct{}
+
+// WithFillZero only mock the fields that hold zero value in this call,
+// the non-nil struct ptr and slice are kept and their children are mocked by the same rule.
+func WithFillZero(ctx context.Context) context.Context {
+	return context.WithValue(ctx, fillZeroKey{}, true)
+}
+
+// keepValue report whether the current value of the field is kept
+func keepValue(ctx context.Context, val reflect.Value) bool {
+	fillZero, _ := ctx.Value(fillZeroKey{}).(bool)
+	return fillZero && !val.IsZero()
+}
+
 func (m *Mock) mockStruct(ctx context.Context, val reflect.Value, fl FieldLevel) (err error) {
 	if fl == nil {
 		fl, err = m.genCache(ctx, val)
@@ -95,11 +186,19 @@ func (m *Mock) mockStructValue(ctx context.Context, val reflect.Value, fl FieldL
 	if ctx.Err() != nil {
 		return ctx.Err()
 	}
-	err = m.mockValue(ctx, val, fl)
+	if ok, err := m.mockOverride(ctx, val, fl); ok || err != nil { //the children are overridden too
+		return err
+	}
+	if !keepValue(ctx, val) {
+		err = m.setValue(ctx, val, fl, m.mockFunc(fl))
+	}
 	if err != nil {
 		return
 	}
 	if fl.IsPtr() {
+		if val.IsNil() {
+			return
+		}
 		val = val.Elem()
 	}
 	for _, field := range fl.GetChildren() {
@@ -115,23 +214,35 @@ func (m *Mock) mockStructValue(ctx context.Context, val reflect.Value, fl FieldL
 			return
 		}
 	}
-	return
+	return afterMock(ctx, val, fl)
 }
 func (m *Mock) mockSliceValue(ctx context.Context, val reflect.Value, fl FieldLevel) (err error) {
-	err = m.mockValue(ctx, val, fl)
+	if ok, err := m.mockOverride(ctx, val, fl); ok || err != nil { //the elements are overridden too
+		return err
+	}
+	if !keepValue(ctx, val) {
+		err = m.setValue(ctx, val, fl, m.mockFunc(fl))
+	}
 	if err != nil {
 		return
 	}
 	if len(fl.GetChildren()) == 0 { //not into
 		return
 	}
-	var rt reflect.Type
+	var (
+		rt       reflect.Type
+		ectx     = ctx
+		indexing = hasOverride(ctx) || hasTrace(ctx)
+	)
 	for i := 0; i < val.Len(); i++ {
+		if indexing {
+			ectx = withSliceIndex(ctx, i)
+		}
 		rt, _ = m.Indirect(val.Index(i).Type())
 		if rt.Kind() == reflect.Struct {
-			err = m.mockStructValue(ctx, val.Index(i), fl.GetChildren()[0])
+			err = m.mockStructValue(ectx, val.Index(i), fl.GetChildren()[0])
 		} else {
-			err = m.mockValue(ctx, val.Index(i), fl.GetChildren()[0])
+			err = m.mockValue(ectx, val.Index(i), fl.GetChildren()[0])
 		}
 		if err != nil {
 			return
@@ -141,7 +252,60 @@ func (m *Mock) mockSliceValue(ctx context.Context, val reflect.Value, fl FieldLe
 }
 
 func (m *Mock) mockValue(ctx context.Context, val reflect.Value, fl FieldLevel) (err error) {
-	if fl.GetMockFunc() == nil {
+	if ok, err := m.mockOverride(ctx, val, fl); ok || err != nil {
+		return err
+	}
+	if keepValue(ctx, val) {
+		return nil
+	}
+	return m.setValue(ctx, val, fl, m.mockFunc(fl))
+}
+
+// MockTag mock the value of the base type or slice by the mock tag as a field, eg: MockTag(ctx, reflect.TypeOf(""), "key=email").
+// the elements of slice are not mocked, it is used for the values out of struct
+func (m *Mock) MockTag(ctx context.Context, rt reflect.Type, tag string) (reflect.Value, error) {
+	fl, err := m.leafField(rt, m.tagPairs(tag))
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	fn := m.mockFunc(fl)
+	if fn == nil {
+		return reflect.Zero(rt), nil
+	}
+	rv, err := fn(ctx, fl)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	if rv.Type() != rt {
+		rv = rv.Convert(rt)
+	}
+	return rv, nil
+}
+
+// tagPairs split the mock tag into the key value pairs
+func (m *Mock) tagPairs(tag string) [][2]string {
+	var tags [][2]string
+	for _, kv := range m.splitTag(tag) {
+		values := strings.SplitN(kv, m.tagSeparator, 2)
+		if len(values) == 1 {
+			values = append(values, "")
+		}
+		tags = append(tags, [2]string{values[0], values[1]})
+	}
+	return tags
+}
+
+// mockOverride mock the field by the override in context, see WithOverride
+func (m *Mock) mockOverride(ctx context.Context, val reflect.Value, fl FieldLevel) (bool, error) {
+	fn := lookupOverride(ctx, fl)
+	if fn == nil {
+		return false, nil
+	}
+	return true, m.setValue(ctx, val, fl, fn)
+}
+
+func (m *Mock) setValue(ctx context.Context, val reflect.Value, fl FieldLevel, fn MockFunc) (err error) {
+	if fn == nil {
 		return
 	}
 	defer func() {
@@ -151,15 +315,32 @@ func (m *Mock) mockValue(ctx context.Context, val reflect.Value, fl FieldLevel)
 		}
 		err = fmt.Errorf("field:%s,err:%v", fl.GetAlias(), e)
 	}()
-	var rv reflect.Value
-	rv, err = fl.GetMockFunc()(ctx, fl)
+	var (
+		rv    reflect.Value
+		entry *TraceEntry
+	)
+	ctx, entry = startTrace(ctx, fl)
+	rv, err = fn(ctx, fl)
 	if err != nil {
 		return
 	}
 	if baseTypes[fl.GetKind()] && fl.GetKind().String() != fl.GetType().String() {
-		rv = rv.Convert(fl.GetType())
+		rv = convertValue(rv, fl)
 	}
 
 	val.Set(rv)
+	entry.finish(val)
 	return
 }
+
+// convertValue convert the base value to the named type, eg: int32 to HobbyType, *int32 to *HobbyType
+func convertValue(rv reflect.Value, fl FieldLevel) reflect.Value {
+	rt := fl.GetType()
+	if fl.IsPtr() {
+		rt = reflect.PointerTo(rt)
+	}
+	if rv.Type() == rt {
+		return rv
+	}
+	return rv.Convert(rt)
+}
@@ -2,10 +2,24 @@ package gomock
 
 import (
 	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/csv"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
 	"reflect"
+	"strconv"
+	"strings"
 	"testing"
+	"testing/quick"
+	"time"
+
+	"gopkg.in/yaml.v3"
 )
 
 type HobbyType int32
@@ -124,3 +138,1255 @@ func TestMockContext(t *testing.T) {
 	b, _ := json.Marshal(human)
 	t.Logf("success: %s", string(b))
 }
+
+type Shelf struct {
+	Id      int64    `json:"id" mock:"key=integer,gte=1,lte=100"`
+	Kind    string   `json:"kind" mock:"key=string,options=novel poetry"`
+	Code    string   `json:"code" mock:"key=string,reg=[A-Z]{2}\\d{3}"`
+	Weight  *float32 `json:"weight" mock:"key=decimal,gt=0.5,lte=9.9"`
+	Hobby   *Hobby   `json:"hobby" mock:"into=1"`
+	Hobbies []*Hobby `json:"hobbies" mock:"gte=1,lte=3,into=1"`
+}
+
+func TestValidate(t *testing.T) {
+	mock := New()
+	shelf := &Shelf{}
+	if err := mock.Struct(shelf); err != nil {
+		t.Fatal(err)
+	}
+	if err := mock.Validate(shelf); err != nil {
+		t.Fatal(err)
+	}
+	shelf.Id = 101
+	shelf.Kind = "comic"
+	shelf.Code = "AB12"
+	shelf.Hobby.Pros[0] = "x"
+	shelf.Hobbies[0].HT = 9
+	err := mock.Validate(shelf)
+	var ve ValidationErrors
+	if !errors.As(err, &ve) {
+		t.Fatalf("expect validation errors, got %v", err)
+	}
+	expects := map[string]string{"Id": MockLte, "Kind": MockOptions, "Code": MockRegExp, "Hobby.Pros.0": MockGte,
+		"Hobbies.0.HT": MockOptions}
+	for _, fe := range ve {
+		if tag, ok := expects[fe.Path]; !ok || tag != fe.Tag {
+			t.Errorf("unexpected error: %v", fe)
+		}
+		delete(expects, fe.Path)
+	}
+	if len(expects) > 0 {
+		t.Errorf("missing errors: %v", expects)
+	}
+	t.Logf("validate: %v", err)
+	//the uint64 above MaxInt64 is compared in the uint64 domain
+	type Counter struct {
+		Hits  uint64 `mock:"key=integer,gte=1"`
+		Limit uint64 `mock:"key=integer,lte=100"`
+	}
+	err = mock.Validate(&Counter{Hits: math.MaxUint64, Limit: math.MaxUint64})
+	if !errors.As(err, &ve) || len(ve) != 1 || ve[0].Path != "Limit" || ve[0].Tag != MockLte {
+		t.Fatalf("expect only the lte error of Limit, got %v", err)
+	}
+	var fe *FieldError
+	if err = mock.ValidateTag(reflect.ValueOf(int32(5)), "key=integer,gte=1,lte=3"); !errors.As(err, &fe) || fe.Tag != MockLte {
+		t.Fatalf("expect the lte error, got %v", err)
+	}
+	if err = mock.ValidateTag(reflect.ValueOf("ab"), "key=string,options=ab cd"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+type Account struct {
+	Name    string   `json:"name" binding:"required,min=3,max=20"`
+	Role    string   `json:"role" binding:"required,oneof=admin guest"`
+	Email   string   `json:"email" binding:"omitempty,email"`
+	Age     *int32   `json:"age" binding:"gte=18,lte=65"`
+	Score   float64  `json:"score" binding:"required"`
+	Rate    float64  `json:"rate" binding:"min=5"`
+	Ratio   float32  `json:"ratio" binding:"omitempty,gt=0.5"`
+	Tags    []string `json:"tags" binding:"min=1,max=3,dive,len=4"`
+	Hobby   *Hobby   `json:"hobby" binding:"required"`
+	Books   []Book   `json:"books" binding:"len=2,dive"`
+	Enabled bool     `json:"enabled" binding:"required"`
+}
+
+func TestValidatorTagSource(t *testing.T) {
+	mock := New()
+	mock.RegisterTagSource(ValidatorTagSource("binding"))
+	account := &Account{}
+	if err := mock.Struct(account); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(account.Name); n < 3 || n > 20 {
+		t.Errorf("name length out of range: %s", account.Name)
+	}
+	if account.Role != "admin" && account.Role != "guest" {
+		t.Errorf("role not in options: %s", account.Role)
+	}
+	if account.Age == nil || *account.Age < 18 || *account.Age > 65 {
+		t.Errorf("age out of range: %v", account.Age)
+	}
+	if account.Rate < 5 || account.Rate > 1005 || account.Ratio <= 0.5 {
+		t.Errorf("decimal out of range: %+v", account)
+	}
+	if account.Score <= 0 || account.Email == "" || account.Hobby == nil || account.Hobby.Id != 5 {
+		t.Errorf("required fields not mocked: %+v", account)
+	}
+	if len(account.Tags) < 1 || len(account.Tags) > 3 || len(account.Tags[0]) != 4 || len(account.Books) != 2 {
+		t.Errorf("slice not mocked: %+v", account)
+	}
+	if err := mock.Validate(account); err != nil {
+		t.Error(err)
+	}
+	//the source returning false is skipped
+	mock = New()
+	mock.RegisterTagSource(func(rs reflect.StructField) (string, bool) {
+		return "key=string,eq=skipped", false
+	})
+	mock.RegisterTagSource(ValidatorTagSource("binding"))
+	if err := mock.Struct(account); err != nil {
+		t.Fatal(err)
+	}
+	if account.Name == "skipped" || (account.Role != "admin" && account.Role != "guest") {
+		t.Errorf("expect the first source is skipped: %+v", account)
+	}
+	b, _ := json.Marshal(account)
+	t.Logf("success: %s", string(b))
+}
+
+type Member struct {
+	Id    int64
+	Email string
+	Level int32 `mock:"key=integer,gte=1,lte=3"`
+	Hobby *Hobby
+}
+
+func TestTagConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mock.yaml")
+	config := "github.com/pigfu/gomock.Member.Id: key=integer,eq=7\ngomock.Member.Id: key=integer,eq=8\n" +
+		"gomock.Member.Email: key=email\n" +
+		"gomock.Member.Hobby: into=1\ngomock.Hobby.Name: key=string,eq=chess\n"
+	if err := os.WriteFile(path, []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	mock := New()
+	if err := mock.LoadTagConfig(path, TagConfigMerge); err != nil {
+		t.Fatal(err)
+	}
+	mock.RegisterTagConfig(map[string]string{"gomock.Member.Level": "eq=9"}, TagConfigMerge)
+	member := &Member{}
+	if err := mock.Struct(member); err != nil {
+		t.Fatal(err)
+	}
+	if member.Id != 7 || member.Email == "" || member.Level != 9 || member.Hobby == nil || member.Hobby.Name != "chess" {
+		t.Errorf("tag config not applied: %+v", member)
+	}
+	mock.RegisterTagConfig(map[string]string{"gomock.Member.Level": "key=integer,options=4"}, TagConfigOverride)
+	if err := mock.Struct(member); err != nil {
+		t.Fatal(err)
+	}
+	if member.Level != 4 {
+		t.Errorf("tag config not override: %+v", member)
+	}
+	if tag := mock.mergeTag("gte=1,lte=5,into=1,key=string,gte=3", "lte=2,into=1,lte=6"); tag !=
+		"gte=1,lte=2,into=1,key=string,gte=3,lte=6" {
+		t.Errorf("unexpected merged tag: %s", tag)
+	}
+}
+
+type Player struct {
+	Age   int32    `json:"age"`
+	Score *float64 `json:"score"`
+	Name  string   `json:"name" mock:"key=string,eq=nobody"`
+	Tags  []string `json:"tags"`
+	Hobby *Hobby   `json:"hobby"`
+}
+
+func TestRuleBuilder(t *testing.T) {
+	mock := New()
+	err := mock.For(&Player{}).Field("Age").Int(18, 65).Field("Score").Decimal(0.5, 9.5).
+		Field("Name").Len(3, 6).Field("Tags").Len(1, 5).Each().Options("a", "b").
+		Field("Hobby.Name").Eq("chess").Err()
+	if err != nil {
+		t.Fatal(err)
+	}
+	player := &Player{}
+	if err = mock.Struct(player); err != nil {
+		t.Fatal(err)
+	}
+	if player.Age < 18 || player.Age > 65 || player.Score == nil || len(player.Name) < 3 || len(player.Name) > 6 ||
+		len(player.Tags) < 1 || len(player.Tags) > 5 || player.Hobby == nil || player.Hobby.Name != "chess" {
+		t.Errorf("rules not applied: %+v", player)
+	}
+	for _, tag := range player.Tags {
+		if tag != "a" && tag != "b" {
+			t.Errorf("tag not in options: %s", tag)
+		}
+	}
+	if err = mock.For(Player{}).Field("Age").Len(1, 2).Err(); err == nil {
+		t.Error("expect type error of Len")
+	}
+	if err = mock.For(Player{}).Field("Tags").Each().Int(1, 2).Err(); err == nil {
+		t.Error("expect type error of Int")
+	}
+	if err = mock.For(Player{}).Field("Nickname").Err(); err == nil {
+		t.Error("expect not found error")
+	}
+	if err = mock.For(Player{}).Field("Age").Int(65, 18).Err(); err == nil {
+		t.Error("expect range error of Int")
+	}
+
+	//the later rules are merged into the registered ones
+	err = mock.For(&Player{}).Field("Name").Len(1, 3).Field("Tags").Eq(2).Err()
+	if err == nil {
+		err = mock.For(&Player{}).Field("Name").Key("string").Err()
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = mock.Struct(player); err != nil {
+		t.Fatal(err)
+	}
+	if len(player.Name) < 1 || len(player.Name) > 3 || len(player.Tags) != 2 || player.Tags[0] != "a" && player.Tags[0] != "b" {
+		t.Errorf("rules not merged: %+v", player)
+	}
+	t.Logf("success: %+v", player)
+}
+
+func TestOverride(t *testing.T) {
+	mock := New()
+	ctx := WithOverride(context.Background(), "Hobby.Name", "chess")
+	ctx = WithOverride(ctx, "Hobby.HT", 3)
+	ctx = WithOverride(ctx, "Weight", 1.5)
+	ctx = WithOverride(ctx, "Hobbies", []*Hobby{{Id: 1}, {Id: 2}, {Id: 3}})
+	ctx = WithOverride(ctx, "Hobbies.*.Name", "reading")
+	ctx = WithOverrideFunc(ctx, "Hobbies.1.Pros", func(_ context.Context, fl FieldLevel) (reflect.Value, error) {
+		return reflect.ValueOf([]string{"patient"}), nil
+	})
+	shelf := &Shelf{}
+	if err := mock.StructCtx(ctx, shelf); err != nil {
+		t.Fatal(err)
+	}
+	if shelf.Hobby.Name != "chess" || shelf.Hobby.HT != OutdoorHobbyType || shelf.Weight == nil || *shelf.Weight != 1.5 {
+		t.Errorf("override not applied: %+v", shelf.Hobby)
+	}
+	if len(shelf.Hobbies) != 3 || shelf.Hobbies[2].Id != 3 || shelf.Hobbies[2].Name != "" {
+		t.Errorf("slice not overridden: %+v", shelf.Hobbies)
+	}
+
+	ctx = WithOverride(context.Background(), "Hobbies.*.Name", "reading")
+	ctx = WithOverrideFunc(ctx, "Hobbies.0.Pros", func(_ context.Context, fl FieldLevel) (reflect.Value, error) {
+		return reflect.ValueOf([]string{"patient"}), nil
+	})
+	if err := mock.StructCtx(ctx, shelf); err != nil {
+		t.Fatal(err)
+	}
+	for i, hobby := range shelf.Hobbies {
+		if hobby.Name != "reading" || i == 0 && (len(hobby.Pros) != 1 || hobby.Pros[0] != "patient") {
+			t.Errorf("element not overridden: %+v", hobby)
+		}
+	}
+	if err := mock.StructCtx(WithOverride(context.Background(), "Id", "one"), shelf); err == nil {
+		t.Error("expect type error")
+	}
+	//the fields of the struct elements are named by the same path, not by the element type
+	err := mock.StructCtx(WithOverride(context.Background(), "Hobbies.*.Name", 1), shelf)
+	if err == nil || !strings.Contains(err.Error(), "field:Hobbies.0.Name,") {
+		t.Errorf("expect the error of Hobbies.0.Name, got %v", err)
+	}
+}
+
+func TestFillZero(t *testing.T) {
+	mock := New()
+	shelf := &Shelf{
+		Id:      1000,
+		Hobby:   &Hobby{Name: "chess"},
+		Hobbies: []*Hobby{{Id: 9}, nil},
+	}
+	hobby := shelf.Hobby
+	if err := mock.StructCtx(WithFillZero(context.Background()), shelf); err != nil {
+		t.Fatal(err)
+	}
+	if shelf.Id != 1000 || shelf.Hobby != hobby || hobby.Name != "chess" || hobby.Id != 5 || len(hobby.Pros) == 0 {
+		t.Errorf("fixture clobbered: %+v", shelf.Hobby)
+	}
+	if len(shelf.Hobbies) != 2 || shelf.Hobbies[0].Id != 9 || shelf.Hobbies[0].Name == "" || shelf.Hobbies[1] == nil {
+		t.Errorf("slice clobbered: %+v", shelf.Hobbies)
+	}
+	if shelf.Kind == "" || shelf.Weight == nil {
+		t.Errorf("zero fields not mocked: %+v", shelf)
+	}
+}
+
+type Address struct {
+	Id      int64   `json:"id" mock:"key=integer,gte=1,lte=99999"`
+	Address string  `json:"address" mock:"key=addr,addr=province city county"`
+	Phone   string  `json:"phone" mock:"key=mobile_phone"`
+	Email   string  `json:"email" mock:"key=email"`
+	Shelf   *Shelf  `json:"shelf" mock:"into=1"`
+	Books   []*Book `json:"books" mock:"gte=0,lte=4,into=1"`
+}
+
+func TestMakeParallel(t *testing.T) {
+	mock := New()
+	one, err := MakeParallel[Address](context.Background(), mock, 50, 1, 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	eight, err := MakeParallel[*Address](context.Background(), mock, 50, 8, 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range one {
+		b1, _ := json.Marshal(one[i])
+		b2, _ := json.Marshal(eight[i])
+		if string(b1) != string(b2) {
+			t.Fatalf("not deterministic:\n%s\n%s", b1, b2)
+		}
+	}
+	ctx := WithRand(context.Background(), rand.New(rand.NewSource(42)))
+	values, err := MakeN[Address](ctx, mock, 3)
+	if err != nil || len(values) != 3 {
+		t.Fatal(values, err)
+	}
+	if _, err = MakeN[int](context.Background(), mock, 1); err == nil {
+		t.Error("expect not a struct error")
+	}
+	if _, err = MakeN[Address](context.Background(), mock, -1); err == nil {
+		t.Error("expect negative n error")
+	}
+	if _, err = MakeParallel[Address](context.Background(), mock, -1, 4, 42); err == nil {
+		t.Error("expect negative n error")
+	}
+	b, _ := json.Marshal(one[0])
+	t.Logf("success: %s", string(b))
+}
+
+func TestReplaceMock(t *testing.T) {
+	mock := New()
+	address := &Address{}
+	if err := mock.Struct(address); err != nil {
+		t.Fatal(err)
+	}
+	if err := mock.RegisterMock(makeEmail, mockEmail); err == nil {
+		t.Error("expect collision error")
+	}
+	err := mock.ReplaceMock(makeEmail, func(_ context.Context, fl FieldLevel) (reflect.Value, error) {
+		return reflect.ValueOf("admin@corp.com"), nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = mock.ReplaceTag(MockAddress, func(_ reflect.Type, key, value string) (TagLevel, error) {
+		return AddressFunc(nil, key, province)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = mock.Struct(address); err != nil {
+		t.Fatal(err)
+	}
+	if address.Email != "admin@corp.com" || strings.Contains(address.Address, " ") {
+		t.Errorf("not replaced: %+v", address)
+	}
+	if err = mock.UnregisterMock(makeMobilePhone); err != nil {
+		t.Fatal(err)
+	}
+	if err = mock.Struct(address); err == nil {
+		t.Error("expect not found mock key error")
+	}
+	if err = mock.UnregisterMock(makeStruct); err == nil {
+		t.Error("expect required error")
+	}
+	if err = mock.UnregisterTag("not_exists"); err == nil {
+		t.Error("expect not support error")
+	}
+}
+
+func TestTrace(t *testing.T) {
+	mock := New()
+	tr := NewTrace()
+	ctx := WithTrace(WithOverride(context.Background(), "Hobbies.*.Name", "chess"), tr)
+	shelf := &Shelf{}
+	if err := mock.StructCtx(ctx, shelf); err != nil {
+		t.Fatal(err)
+	}
+	branches := make(map[string]*TraceEntry)
+	for _, entry := range tr.Entries() {
+		branches[entry.Path] = entry
+	}
+	expects := map[string]string{"Id": BranchRange, "Kind": BranchOptions, "Code": BranchRegExp, "Hobby.Id": BranchEqual,
+		"Hobbies": BranchRange, "Hobbies.0.Name": BranchOverride}
+	for path, branch := range expects {
+		if entry, ok := branches[path]; !ok || entry.Branch != branch {
+			t.Errorf("unexpected entry of %s: %+v", path, entry)
+		}
+	}
+	if branches["Kind"].Value != shelf.Kind || branches["Kind"].Key != makeString {
+		t.Errorf("unexpected value: %+v", branches["Kind"])
+	}
+	b := &strings.Builder{}
+	if err := tr.WriteTable(b); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := json.Marshal(tr); err != nil {
+		t.Fatal(err)
+	}
+	t.Logf("trace:\n%s", b.String())
+}
+
+type Line struct {
+	Price int64 `json:"price" mock:"key=integer,gte=1,lte=100"`
+	Count int64 `json:"count" mock:"key=integer,gte=1,lte=5"`
+}
+type Invoice struct {
+	Code  string  `json:"code" mock:"key=string,gte=6,lte=12"`
+	Lines []*Line `json:"lines" mock:"gte=1,lte=5,into=1"`
+	Total int64   `json:"total"`
+}
+
+func (iv *Invoice) AfterMock(_ context.Context) error {
+	for _, line := range iv.Lines {
+		iv.Total += line.Price * line.Count
+	}
+	return nil
+}
+
+func TestMiddleware(t *testing.T) {
+	mock, wraps := New(), 0
+	if err := mock.Struct(&Invoice{}); err != nil { //the cached tree is invalidated by Use
+		t.Fatal(err)
+	}
+	mock.Use(func(next MockFunc) MockFunc {
+		wraps++
+		return func(ctx context.Context, fl FieldLevel) (reflect.Value, error) {
+			rv, err := next(ctx, fl)
+			if err != nil || fl.GetKind() != reflect.String {
+				return rv, err
+			}
+			return reflect.ValueOf(strings.ToUpper(rv.String())[:4]), nil
+		}
+	})
+	invoice := &Invoice{}
+	if err := mock.Struct(invoice); err != nil {
+		t.Fatal(err)
+	}
+	if len(invoice.Code) != 4 || strings.ToUpper(invoice.Code) != invoice.Code {
+		t.Errorf("middleware not applied: %s", invoice.Code)
+	}
+	if n := wraps; mock.Struct(&Invoice{}) != nil || wraps != n {
+		t.Errorf("middlewares rebuilt per value: %d -> %d", n, wraps)
+	}
+	total := int64(0)
+	for _, line := range invoice.Lines {
+		total += line.Price * line.Count
+	}
+	if total == 0 || total != invoice.Total {
+		t.Errorf("hook not called: %+v", invoice)
+	}
+}
+
+type Money int64
+
+func (mo *Money) MockValue(ctx context.Context, fl FieldLevel) error {
+	n := fl.GetTags().Key(MockLte).GetInt64()
+	if n <= 0 {
+		n = 10
+	}
+	*mo = Money(100 * (1 + GetRand(ctx).Int63n(n)))
+	return nil
+}
+
+type Code struct {
+	Prefix string
+	Number int
+}
+
+func (c *Code) MockValue(ctx context.Context, _ FieldLevel) error {
+	c.Prefix, c.Number = "SN", 1+GetRand(ctx).Intn(999)
+	return nil
+}
+
+type Order struct {
+	Price  Money  `json:"price" mock:"lte=10"`
+	Refund *Money `json:"refund"`
+	Code   Code   `json:"code"`
+	Codes  []Code `json:"codes" mock:"eq=2,into=1"`
+	Fixed  Money  `json:"fixed" mock:"key=integer,eq=1"`
+}
+
+func TestMocker(t *testing.T) {
+	mock := New()
+	order := &Order{}
+	if err := mock.Struct(order); err != nil {
+		t.Fatal(err)
+	}
+	if order.Price < 100 || order.Price > 1000 || order.Price%100 != 0 || order.Refund == nil || *order.Refund%100 != 0 {
+		t.Errorf("money not mocked: %+v", order)
+	}
+	if order.Code.Prefix != "SN" || len(order.Codes) != 2 || order.Codes[1].Prefix != "SN" || order.Fixed != 1 {
+		t.Errorf("code not mocked: %+v", order)
+	}
+}
+
+type Labels map[string]string
+
+func (l *Labels) MockValue(context.Context, FieldLevel) error {
+	*l = Labels{"env": "test"}
+	return nil
+}
+
+type Deploy struct {
+	Name   string `mock:"key=string,eq=app"`
+	Labels Labels
+	Extra  *Labels
+}
+
+func TestMockerNotSupportKind(t *testing.T) {
+	deploy := &Deploy{}
+	if err := New().Struct(deploy); err != nil { //the untagged map is skipped as before
+		t.Fatal(err)
+	}
+	if deploy.Name != "app" || deploy.Labels != nil || deploy.Extra != nil {
+		t.Errorf("unexpected deploy: %+v", deploy)
+	}
+}
+
+type Ticket struct {
+	Seat   int32    `mock:"key=integer,gte=1,lte=100"`
+	Price  float64  `mock:"key=decimal,gte=-1.5,lt=2.5"`
+	Holder string   `mock:"key=string,gte=2,lte=6"`
+	Level  string   `mock:"key=string,options=low mid high"`
+	Tags   []string `mock:"key=slice,lte=3,into=1,key=string,eq=x"`
+}
+
+func TestBoundary(t *testing.T) {
+	mock := New()
+	tickets, err := MakeParallel[Ticket](WithBoundary(context.Background()), mock, 20, 4, 1) //no race
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, ticket := range tickets {
+		if err = mock.Validate(&ticket); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tickets, err = Boundaries[Ticket](context.Background(), mock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tickets) != 5 { //the price has zero
+		t.Fatalf("expect 5 tickets, got %d", len(tickets))
+	}
+	var (
+		seats   = map[int32]bool{}
+		prices  = map[float64]bool{}
+		holders = map[int]bool{}
+		levels  = map[string]bool{}
+		tags    = map[int]bool{}
+	)
+	for _, ticket := range tickets {
+		if err = mock.Validate(&ticket); err != nil {
+			t.Fatal(err)
+		}
+		seats[ticket.Seat], prices[ticket.Price] = true, true
+		holders[len(ticket.Holder)], levels[ticket.Level], tags[len(ticket.Tags)] = true, true, true
+	}
+	for _, seat := range []int32{1, 2, 99, 100} {
+		if !seats[seat] {
+			t.Errorf("missing seat %d", seat)
+		}
+	}
+	for _, price := range []float64{-1.5, -1.4, 0, 2.3, 2.4} {
+		if !prices[price] {
+			t.Errorf("missing price %v", price)
+		}
+	}
+	for _, n := range []int{2, 3, 5, 6} {
+		if !holders[n] {
+			t.Errorf("missing holder length %d", n)
+		}
+	}
+	if !levels["low"] || !levels["high"] || levels["mid"] {
+		t.Errorf("expect the first and last level, got %v", levels)
+	}
+	for _, n := range []int{0, 1, 2, 3} {
+		if !tags[n] {
+			t.Errorf("missing tags length %d", n)
+		}
+	}
+
+	var ticket Ticket
+	if err = mock.StructCtx(WithBoundary(context.Background()), &ticket); err != nil {
+		t.Fatal(err)
+	}
+	if !seats[ticket.Seat] || !prices[ticket.Price] {
+		t.Errorf("not a boundary value: %+v", ticket)
+	}
+	b, _ := json.Marshal(tickets)
+	t.Logf("success: %s", string(b))
+}
+
+type Signup struct {
+	Name  string   `mock:"key=string,gte=3,lte=8"`
+	Age   *int     `mock:"key=integer,gte=18,lte=60"`
+	Score float32  `mock:"key=decimal,gt=0,lte=9.5"`
+	Role  string   `mock:"key=string,options=admin guest"`
+	Code  string   `mock:"key=string,reg=[0-9]{4}"`
+	Tags  []string `mock:"key=slice,gte=1,lte=3,into=1,key=string,eq=x"`
+}
+
+func TestInvalid(t *testing.T) {
+	var (
+		mock  = New()
+		ctx   = WithRand(context.Background(), rand.New(rand.NewSource(7)))
+		paths = map[string]bool{}
+	)
+	for i := 0; i < 100; i++ {
+		var signup Signup
+		fe, err := mock.InvalidCtx(ctx, &signup)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = mock.Validate(&signup)
+		var ve ValidationErrors
+		if !errors.As(err, &ve) || len(ve) != 1 || ve[0].Path != fe.Path || ve[0].Tag != fe.Tag {
+			t.Fatalf("expect exactly %v, got %v", fe, err)
+		}
+		paths[fe.Path+":"+fe.Tag] = true
+	}
+	for _, path := range []string{"Name:gte", "Name:lte", "Age:gte", "Age:lte", "Score:gt", "Score:lte",
+		"Role:options", "Code:reg", "Tags:gte", "Tags:lte", "Tags.0:eq"} {
+		if !paths[path] {
+			t.Errorf("missing violation %s", path)
+		}
+	}
+	if _, err := mock.Invalid(&struct{ Name string }{}); err == nil {
+		t.Error("expect no mock tag can be violated")
+	}
+	//the candidates out of the kind range are skipped
+	var limits struct {
+		Small int8  `mock:"key=integer,eq=127"`
+		Large int64 `mock:"key=integer,eq=9223372036854775807"`
+	}
+	for i := 0; i < 20; i++ {
+		fe, err := mock.InvalidCtx(ctx, &limits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if limits.Small != 127 && limits.Small != 126 || limits.Large != math.MaxInt64 && limits.Large != math.MaxInt64-1 {
+			t.Fatalf("expect the value below the limit, got %+v by %v", limits, fe)
+		}
+	}
+	t.Logf("success: %v", paths)
+}
+
+type Platform struct {
+	OS      string   `mock:"key=string,options=linux mac windows"`
+	Browser string   `mock:"key=string,options=chrome firefox safari,weights=1 5 3"`
+	Lang    int      `mock:"key=integer,options=1 2"`
+	Tags    []string `mock:"key=slice,gte=1,lte=2,into=1,key=string,options=a b"`
+	Name    string   `mock:"key=string,gte=3,lte=5"`
+}
+
+func TestPairwise(t *testing.T) {
+	mock := New()
+	platforms, err := Pairwise[*Platform](context.Background(), mock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(platforms) >= 3*3*2*2 {
+		t.Fatalf("too many platforms: %d", len(platforms))
+	}
+	if platforms[0].Browser != "firefox" {
+		t.Errorf("expect the option with the largest weight first, got %s", platforms[0].Browser)
+	}
+	covered := map[string]bool{}
+	for _, p := range platforms {
+		if err = mock.Validate(p); err != nil {
+			t.Fatal(err)
+		}
+		for _, tag := range p.Tags {
+			if tag != p.Tags[0] {
+				t.Fatalf("expect the same tags, got %v", p.Tags)
+			}
+		}
+		values := []string{"os:" + p.OS, "browser:" + p.Browser, "lang:" + strconv.Itoa(p.Lang), "tag:" + p.Tags[0]}
+		for i := range values {
+			for j := i + 1; j < len(values); j++ {
+				covered[values[i]+","+values[j]] = true
+			}
+		}
+	}
+	if len(covered) != 3*3+3*2+3*2+3*2+3*2+2*2 {
+		t.Errorf("not all pairs are covered: %d", len(covered))
+	}
+	if _, err = Pairwise[int](context.Background(), mock); err == nil {
+		t.Error("expect not a struct error")
+	}
+	t.Logf("success: %d platforms", len(platforms))
+}
+
+type Cart struct {
+	User  string   `mock:"key=string,gte=3,lte=10"`
+	Qty   int      `mock:"key=integer,gte=1,lte=100"`
+	Rate  *float64 `mock:"key=decimal,gte=0.5,lte=9.5"`
+	Items []*Book  `mock:"lte=5,into=1"`
+}
+
+type Voter struct {
+	Age  *int   `json:"age" mock:"key=integer,gte=23,lte=99"`
+	Name string `json:"name" mock:"key=string,gte=3,lte=10"`
+}
+
+// fakeT record the failure of Check
+type fakeT struct {
+	msg string
+}
+
+func (ft *fakeT) Helper() {}
+func (ft *fakeT) Fatalf(format string, args ...any) {
+	ft.msg = fmt.Sprintf(format, args...)
+}
+
+func TestCheck(t *testing.T) {
+	mock := New()
+	seed := int64(42)
+	Check(t, mock, func(c Cart) bool {
+		return c.Qty >= 1 && c.Qty <= 100 && len(c.User) >= 3
+	}, CheckConfig{Count: 50, Seed: &seed})
+
+	ft := &fakeT{}
+	Check(ft, mock, func(c *Cart) bool {
+		return c.Qty < 50
+	}, CheckConfig{Seed: &seed})
+	if !strings.Contains(ft.msg, "shrunk by") || !strings.Contains(ft.msg, `"Qty":50,"Rate":0.5,"Items":[]}`) {
+		t.Fatalf("unexpected failure: %s", ft.msg)
+	}
+	value, steps, err := shrink(mock, Cart{User: "abcdefg", Qty: 80, Items: []*Book{{Id: 5, Name: "abcd"}, {Id: 5, Name: "abcd"}, {Id: 5, Name: "abcd"}}}, func(c Cart) bool {
+		return len(c.User) < 5 || len(c.Items) < 2
+	}, 100)
+	if err != nil || steps == 0 || len(value.User) != 5 || len(value.Items) != 2 || value.Qty != 1 {
+		t.Fatalf("unexpected shrunk value: %+v %d %v", value, steps, err)
+	}
+
+	ft = &fakeT{} //the ptr is not shrunk to nil, the nil dereference is not the failure of prop
+	Check(ft, mock, func(u Voter) bool {
+		return *u.Age < 0 || len(u.Name) < 2
+	}, CheckConfig{Seed: &seed})
+	if !strings.Contains(ft.msg, `shrunk by`) || !strings.Contains(ft.msg, `{"age":23,"name":"`) {
+		t.Fatalf("unexpected failure: %s", ft.msg)
+	}
+
+	ft, seed = &fakeT{}, 0
+	Check(ft, mock, func(c Cart) bool {
+		panic("boom")
+	}, CheckConfig{Count: 1, Seed: &seed})
+	if !strings.Contains(ft.msg, "property failed on the value 0,seed:0") {
+		t.Fatalf("expect the panic is a failure: %s", ft.msg)
+	}
+	t.Logf("success: %s", ft.msg)
+}
+
+func TestFromBytes(t *testing.T) {
+	mock := New()
+	data := []byte("the fuzzer mutates these bytes to mock the value")
+	a, b := FromBytes[*Address](mock, data), FromBytes[*Address](mock, data)
+	b1, _ := json.Marshal(a)
+	b2, _ := json.Marshal(b)
+	if string(b1) != string(b2) {
+		t.Fatalf("not deterministic:\n%s\n%s", b1, b2)
+	}
+	empty := FromBytes[Address](mock, nil)
+	if empty.Id != 1 || len(empty.Books) != 0 {
+		t.Errorf("expect the minimal value, got %+v", empty)
+	}
+	if _, err := FromBytesCtx[int](context.Background(), mock, data); err == nil {
+		t.Error("expect not a struct error")
+	}
+	t.Logf("success: %s", string(b1))
+}
+
+func FuzzFromBytes(f *testing.F) {
+	mock := New()
+	f.Add([]byte{})
+	f.Add([]byte("gomock"))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		cart := FromBytes[Cart](mock, data)
+		if err := mock.Validate(&cart); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func (Cart) Generate(r *rand.Rand, size int) reflect.Value {
+	return Generate[Cart](New(), r, size)
+}
+
+func TestQuick(t *testing.T) {
+	mock := New()
+	config := &quick.Config{MaxCount: 50, Rand: rand.New(rand.NewSource(42))}
+	err := quick.Check(func(c Cart) bool {
+		return mock.Validate(&c) == nil && len(c.Items) <= 5
+	}, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = quick.Check(func(q Quick[*Shelf]) bool {
+		return mock.Validate(q.Value) == nil
+	}, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	//the registrations of the given Mock are used
+	mock.RegisterTagConfig(map[string]string{"github.com/pigfu/gomock.Hobby.Name": "eq=quick"}, TagConfigMerge)
+	prop := func(q Quick[*Shelf], shelf Shelf, c Cart, n int) bool {
+		return q.Value.Hobby.Name == "quick" && shelf.Hobby.Name == "quick" && mock.Validate(&c) == nil
+	}
+	config.Values = QuickValues(mock, prop)
+	if err = quick.Check(prop, config); err != nil {
+		t.Fatal(err)
+	}
+	cart := Generate[Cart](mock, rand.New(rand.NewSource(1)), 0).Interface().(Cart)
+	if len(cart.User) != 3 || len(cart.Items) != 0 {
+		t.Errorf("expect the length is limited by size: %+v", cart)
+	}
+	cart = Generate[Cart](mock, rand.New(rand.NewSource(1)), 4).Interface().(Cart)
+	if len(cart.User) < 3 || len(cart.User) > 4 || len(cart.Items) > 4 {
+		t.Errorf("expect the length is limited by size: %+v", cart)
+	}
+	t.Logf("success: %+v", cart)
+}
+
+type Profile struct {
+	Email   string   `json:"email" mock:"key=email"`
+	Created string   `json:"created" mock:"key=time,time=2006-01-02T15:04:05Z07:00"`
+	Age     *int32   `json:"age" mock:"key=integer,gte=18,lt=60"`
+	Score   float64  `json:"score" mock:"key=decimal,gt=0,lte=9.5"`
+	Role    string   `json:"role" mock:"key=string,options=admin guest"`
+	Code    string   `json:"code" mock:"key=string,reg=[0-9]{4}"`
+	Tags    []string `json:"tags,omitempty" mock:"gte=1,lte=3,into=1,key=string,gte=2,lte=6"`
+	Hobby   *Hobby   `json:"hobby" mock:"into=1"`
+	Skipped string   `json:"-" mock:"key=string"`
+}
+
+func TestSchema(t *testing.T) {
+	mock := New()
+	schema, err := mock.Schema(&Profile{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := json.Marshal(schema)
+	for _, expect := range []string{
+		`"email":{"type":"string","format":"email"}`,
+		`"created":{"type":"string","format":"date-time"}`,
+		`"$schema":"https://json-schema.org/draft/2020-12/schema"`,
+		`"age":{"anyOf":[{"type":"integer","format":"int32","minimum":18,"exclusiveMaximum":60},{"type":"null"}]}`,
+		`"score":{"type":"number","format":"double","maximum":9.5,"exclusiveMinimum":0}`,
+		`"role":{"type":"string","enum":["admin","guest"]}`,
+		`"code":{"type":"string","pattern":"^(?:[0-9]{4})$"}`,
+		`"tags":{"type":"array","items":{"type":"string","minLength":2,"maxLength":6},"minItems":1,"maxItems":3}`,
+		`"id":{"type":"integer","format":"int64","const":5}`,
+		`"hobby":{"anyOf":[{"type":"object",`,
+	} {
+		if !strings.Contains(string(b), expect) {
+			t.Errorf("missing %s in %s", expect, b)
+		}
+	}
+	if strings.Contains(string(b), "Skipped") {
+		t.Error("expect the json - is skipped")
+	}
+	//the type of the same name in the test is another component
+	type Hobby struct {
+		Title string `json:"title" mock:"key=string"`
+	}
+	doc, err := mock.OpenAPI(OpenAPIInfo{Title: "profile", Version: "1.0"}, Profile{}, Hobby{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	components := doc.Components.Schemas
+	hobby := components["Profile"].Properties["hobby"]
+	if doc.OpenAPI != OpenAPIVersion || components["Profile"] == nil || components["Hobby"] == nil ||
+		len(hobby.AnyOf) != 2 || hobby.AnyOf[0].Ref != "#/components/schemas/Hobby" || hobby.AnyOf[1].Type != schemaNull {
+		t.Fatalf("unexpected components: %+v", components)
+	}
+	if local := components["gomock.Hobby"]; local == nil || local.Properties["title"] == nil {
+		t.Fatalf("expect the local Hobby is not overwritten: %+v", components)
+	}
+	if _, err = mock.Schema(1); err == nil {
+		t.Error("expect not a struct error")
+	}
+	t.Logf("success: %s", b)
+}
+
+const userOpenAPI = `
+openapi: 3.1.0
+components:
+  schemas:
+    User:
+      type: object
+      properties:
+        id: {type: integer, minimum: 1, maximum: 100}
+        email: {type: string, format: email}
+        name: {type: [string, "null"], minLength: 3, maxLength: 8}
+        role: {enum: [admin, guest]}
+        code: {type: string, pattern: "^[A-Z]{2}[0-9]{3}$"}
+        score: {type: number, exclusiveMinimum: 0, maximum: 9.5}
+        tags: {type: array, items: {type: string, maxLength: 4}, minItems: 1, maxItems: 3}
+        created: {type: string, format: date-time}
+        manager: {$ref: "#/components/schemas/User"}
+`
+
+func TestMockSchema(t *testing.T) {
+	mock := New()
+	doc, err := ParseSchema([]byte(userOpenAPI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := WithRand(context.Background(), rand.New(rand.NewSource(42)))
+	value, err := mock.MockSchema(ctx, doc, "User")
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := value.(map[string]any)
+	if id := user["id"].(int64); id < 1 || id > 100 {
+		t.Errorf("id out of range: %d", id)
+	}
+	if name := user["name"].(string); len(name) < 3 || len(name) > 8 {
+		t.Errorf("name length out of range: %s", name)
+	}
+	if !matchRegExp("[A-Z]{2}[0-9]{3}", user["code"].(string)) || !strings.Contains(user["email"].(string), "@") {
+		t.Errorf("unexpected user: %v", user)
+	}
+	if score := user["score"].(float64); score <= 0 || score > 9.5 {
+		t.Errorf("score out of range: %v", score)
+	}
+	if tags := user["tags"].([]any); len(tags) < 1 || len(tags) > 3 {
+		t.Errorf("tags length out of range: %v", tags)
+	}
+	if _, err = time.Parse(time.RFC3339, user["created"].(string)); err != nil {
+		t.Error(err)
+	}
+	if _, ok := user["manager"].(map[string]any); !ok {
+		t.Errorf("expect the manager: %v", user)
+	}
+
+	//the schema exported from the tags mocks the valid value
+	components, err := mock.Components(Profile{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := json.Marshal(map[string]any{"components": map[string]any{"schemas": components}})
+	if doc, err = ParseSchema(b); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 20; i++ {
+		data, err := mock.MockSchemaJSON(ctx, doc, "Profile")
+		if err != nil {
+			t.Fatal(err)
+		}
+		var profile Profile
+		if err = json.Unmarshal(data, &profile); err != nil {
+			t.Fatal(err)
+		}
+		if err = mock.Validate(&profile); err != nil {
+			t.Fatalf("%s: %v", data, err)
+		}
+	}
+	//the type array with null and the exclusive bounds of the integer
+	if doc, err = ParseSchema([]byte(`{"properties":{"level":{"type":"integer","exclusiveMinimum":5,"exclusiveMaximum":7},` +
+		`"nick":{"type":["string","null"],"maxLength":4}}}`)); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 20; i++ {
+		value, err := mock.MockSchema(ctx, doc, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		object := value.(map[string]any)
+		if level := object["level"].(int64); level != 6 {
+			t.Fatalf("expect the level is 6: %d", level)
+		}
+		if nick, ok := object["nick"].(string); !ok || len(nick) > 4 {
+			t.Fatalf("unexpected nick: %v", object["nick"])
+		}
+	}
+	if _, err = mock.MockSchema(ctx, doc, "Order"); err == nil {
+		t.Error("expect not found error")
+	}
+	b, _ = json.Marshal(user)
+	t.Logf("success: %s", b)
+}
+
+func TestGenStruct(t *testing.T) {
+	mock := New()
+	if _, err := mock.GenStruct(1); err == nil {
+		t.Error("expect not a struct error")
+	}
+	gs, err := mock.GenStruct(&Book{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := mock.GenStruct(Book{}); again != gs {
+		t.Error("expect the prepared struct reused")
+	}
+	ctx := context.Background()
+	if id, err := GenInteger[int64](ctx, gs.Field("Id")); err != nil || id != 5 {
+		t.Errorf("unexpected id: %d, %v", id, err)
+	}
+	if gs.Field("Unknown") != nil {
+		t.Error("expect nil for the unknown field")
+	}
+
+	//the replaced mock function rebuilds the prepared struct
+	err = mock.ReplaceMock("string", func(context.Context, FieldLevel) (reflect.Value, error) {
+		return reflect.ValueOf("replaced"), nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gs, err = mock.GenStruct(&Book{}); err != nil {
+		t.Fatal(err)
+	}
+	if name, err := GenString[string](ctx, gs.Field("Name")); err != nil || name != "replaced" {
+		t.Errorf("unexpected name: %s, %v", name, err)
+	}
+	if _, err = GenDecimal[float64](ctx, gs.Field("Name")); err == nil {
+		t.Error("expect not a decimal error")
+	}
+}
+
+type Employee struct {
+	Id      int64    `json:"id" yaml:"id" mock:"key=integer,gte=1,lte=100"`
+	Name    string   `json:"name" yaml:"name" mock:"key=string,gte=3,lte=6"`
+	Hobby   *Hobby   `json:"hobby" yaml:"hobby" mock:"into=1"`
+	Hobbies []*Hobby `json:"hobbies" yaml:"hobbies" mock:"gte=1,lte=2,into=1"`
+	Tags    []string `json:"tags" yaml:"tags" mock:"eq=2,into=1,key=string,options=a b"`
+}
+
+func TestExport(t *testing.T) {
+	mock := New()
+	ctx := WithRand(context.Background(), rand.New(rand.NewSource(1)))
+	out := &strings.Builder{}
+	if err := WriteCSV[Employee](ctx, mock, out, 5); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(strings.NewReader(out.String())).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	header := strings.Join(records[0], ",")
+	if len(records) != 6 || len(records[0]) != 28 ||
+		!strings.HasPrefix(header, "Id,Name,Hobby.Id,Hobby.HT,Hobby.Name,Hobby.Pros.0,") ||
+		!strings.HasSuffix(header, "Hobbies.1.Pros.4,Tags.0,Tags.1") {
+		t.Fatalf("unexpected csv:\n%s", out)
+	}
+	for _, record := range records[1:] {
+		if id, err := strconv.Atoi(record[0]); err != nil || id < 1 || id > 100 {
+			t.Errorf("unexpected id: %s", record[0])
+		}
+		if record[2] != "5" || record[26] == "" || record[27] == "" {
+			t.Errorf("unexpected record: %v", record)
+		}
+	}
+
+	//the element columns are limited by the size
+	out.Reset()
+	if err = WriteCSV[*Employee](WithSize(ctx, 1), mock, out, 1); err != nil {
+		t.Fatal(err)
+	}
+	if header, _, _ := strings.Cut(out.String(), "\n"); strings.Contains(header, "Hobby.Pros.1") ||
+		strings.Contains(header, "Hobbies.1") || !strings.Contains(header, "Tags.1") {
+		t.Errorf("unexpected header: %s", header)
+	}
+
+	out.Reset()
+	if err = WriteNDJSON[Employee](ctx, mock, out, 3); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	var employee Employee
+	if len(lines) != 3 || json.Unmarshal([]byte(lines[2]), &employee) != nil || employee.Hobby == nil {
+		t.Errorf("unexpected ndjson:\n%s", out)
+	}
+
+	out.Reset()
+	if err = WriteYAML[Employee](ctx, mock, out, 2); err != nil {
+		t.Fatal(err)
+	}
+	decoder, count := yaml.NewDecoder(strings.NewReader(out.String())), 0
+	for ; decoder.Decode(&employee) == nil; count++ {
+		if employee.Id < 1 || len(employee.Tags) != 2 {
+			t.Errorf("unexpected yaml: %+v", employee)
+		}
+	}
+	if count != 2 || !strings.HasPrefix(out.String(), "id: ") {
+		t.Errorf("unexpected yaml:\n%s", out)
+	}
+	if err = WriteCSV[int](ctx, mock, out, 1); err == nil {
+		t.Error("expect not a struct error")
+	}
+
+	//the slice without the max length is a json cell, the longer slice is an error
+	type Team struct {
+		Name    string   `mock:"key=string,gte=3,lte=6"`
+		Members []string `mock:"gte=2,into=1,key=string,options=a b"`
+		Scores  []int64  `mock:"lte=2,into=1,key=integer,gte=1,lte=9"`
+	}
+	out.Reset()
+	if err = WriteCSV[Team](ctx, mock, out, 3); err != nil {
+		t.Fatal(err)
+	}
+	if records, err = csv.NewReader(strings.NewReader(out.String())).ReadAll(); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(records[0], ",") != "Name,Members,Scores.0,Scores.1" {
+		t.Fatalf("unexpected header: %v", records[0])
+	}
+	for _, record := range records[1:] {
+		var members []string
+		if err = json.Unmarshal([]byte(record[1]), &members); err != nil || len(members) < 2 {
+			t.Errorf("unexpected members: %s", record[1])
+		}
+	}
+	out.Reset()
+	if err = WriteCSV[Team](WithSize(ctx, 3), mock, out, 1); err != nil {
+		t.Fatal(err)
+	}
+	if header, _, _ := strings.Cut(out.String(), "\n"); header != "Name,Members.0,Members.1,Members.2,Scores.0,Scores.1" {
+		t.Errorf("unexpected header: %s", header)
+	}
+	err = WriteCSV[Team](WithOverride(ctx, "Scores", []int64{1, 2, 3}), mock, out, 1)
+	if err == nil || !strings.Contains(err.Error(), "greater than the columns") {
+		t.Errorf("expect the longer slice error, got %v", err)
+	}
+	t.Logf("success:\n%s", records[0])
+}
+
+type SeedStatus string
+
+func (s SeedStatus) Value() (driver.Value, error) {
+	return strings.ToUpper(string(s)), nil
+}
+
+type SeedBase struct {
+	ID        int64  `db:"id" mock:"key=integer,gte=1,lte=1000"`
+	CreatedAt string `mock:"key=time,time=2006-01-02"`
+}
+
+type SeedUser struct {
+	SeedBase `mock:"into=1"`
+	Name     string     `gorm:"column:user_name;size:32" mock:"key=string,options=O'Brien"`
+	Path     string     `mock:"key=string,options=a\\b"`
+	Email    *string    `mock:"key=email"`
+	Score    float64    `mock:"key=decimal,gte=0,lte=100"`
+	Status   SeedStatus `mock:"key=string,options=on off"`
+	Secret   string     `db:"-" mock:"key=string"`
+	Books    []*Book    `mock:"eq=1,into=1"`
+	Note     string
+}
+
+// seedConnector record the statements executed through database/sql
+type seedConnector struct {
+	queries []string
+	args    [][]driver.NamedValue
+}
+
+func (c *seedConnector) Connect(context.Context) (driver.Conn, error) { return c, nil }
+func (c *seedConnector) Driver() driver.Driver                        { return nil }
+func (c *seedConnector) Prepare(string) (driver.Stmt, error)          { return nil, errors.New("not support") }
+func (c *seedConnector) Close() error                                 { return nil }
+func (c *seedConnector) Begin() (driver.Tx, error)                    { return nil, errors.New("not support") }
+func (c *seedConnector) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.queries, c.args = append(c.queries, query), append(c.args, args)
+	return driver.RowsAffected(1), nil
+}
+
+func TestSQL(t *testing.T) {
+	mock := New()
+	ctx := WithRand(context.Background(), rand.New(rand.NewSource(1)))
+	out := &strings.Builder{}
+	if err := WriteInsert[SeedUser](ctx, mock, out, 3, SQLConfig{Dialect: Postgres, Batch: 2}); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 || strings.Count(lines[0], "), (") != 1 || strings.Contains(lines[1], "), (") ||
+		!strings.HasPrefix(lines[0], `INSERT INTO "seed_users" ("id", "created_at", "user_name", "path", "email", "score", "status") VALUES (`) ||
+		!strings.Contains(lines[0], `'O''Brien', 'a\b', '`) ||
+		!strings.HasSuffix(lines[0], "N');") && !strings.HasSuffix(lines[0], "F');") {
+		t.Errorf("unexpected postgres:\n%s", out)
+	}
+
+	out.Reset()
+	if err := WriteInsert[*SeedUser](ctx, mock, out, 1, SQLConfig{Dialect: MySQL}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(out.String(), "INSERT INTO `seed_users` (`id`, `created_at`, `user_name`,") ||
+		!strings.Contains(out.String(), `'O\'Brien', 'a\\b', '`) {
+		t.Errorf("unexpected mysql:\n%s", out)
+	}
+
+	out.Reset()
+	if err := WriteInsert[SeedUser](ctx, mock, out, 1, SQLConfig{Dialect: SQLite, Table: "main.users"}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(out.String(), `INSERT INTO "main"."users" ("id",`) {
+		t.Errorf("unexpected sqlite:\n%s", out)
+	}
+
+	conn := &seedConnector{}
+	db := sql.OpenDB(conn)
+	defer db.Close()
+	if err := ExecInsert[SeedUser](ctx, mock, db, 3, SQLConfig{Dialect: Postgres, Batch: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if len(conn.queries) != 2 || len(conn.args[0]) != 14 || len(conn.args[1]) != 7 ||
+		!strings.HasSuffix(conn.queries[0], "($8, $9, $10, $11, $12, $13, $14)") {
+		t.Fatalf("unexpected execs: %q", conn.queries)
+	}
+	if id, ok := conn.args[1][0].Value.(int64); !ok || id < 1 || id > 1000 {
+		t.Errorf("unexpected id: %v", conn.args[1][0].Value)
+	}
+	if status := conn.args[1][6].Value; status != "ON" && status != "OFF" {
+		t.Errorf("unexpected status: %v", status)
+	}
+
+	table := &sqlTable{dialect: MySQL}
+	for value, want := range map[driver.Value]string{nil: "NULL", true: "1", 1.5: "1.5", "a\nb": `'a\nb'`} {
+		if literal, err := table.literal(value); err != nil || literal != want {
+			t.Errorf("unexpected literal of %v: %s", value, literal)
+		}
+	}
+	if literal, _ := (&sqlTable{dialect: Postgres}).literal([]byte{0xde, 0xad}); literal != `'\xdead'` {
+		t.Errorf("unexpected bytes: %s", literal)
+	}
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.FixedZone("", 8*3600))
+	for dialect, want := range map[SQLDialect]string{Postgres: "'2024-01-02 03:04:05+08:00'",
+		MySQL: "'2024-01-01 19:04:05'", SQLite: "'2024-01-02 03:04:05+08:00'"} {
+		if literal, _ := (&sqlTable{dialect: dialect}).literal(ts); literal != want {
+			t.Errorf("unexpected time of %s: %s", dialect, literal)
+		}
+	}
+	for name, want := range map[string]string{"UserID": "user_id", "HTTPServer": "http_server", "Category": "category"} {
+		if got := snakeCase(name); got != want {
+			t.Errorf("unexpected snake case of %s: %s", name, got)
+		}
+	}
+	for name, want := range map[string]string{"category": "categories", "box": "boxes", "day": "days", "user": "users"} {
+		if got := plural(name); got != want {
+			t.Errorf("unexpected plural of %s: %s", name, got)
+		}
+	}
+	if got := tableName(reflect.TypeOf(struct{}{})); got != "" {
+		t.Errorf("unexpected anonymous table: %s", got)
+	}
+	if err := WriteInsert[SeedUser](ctx, mock, out, 1, SQLConfig{Dialect: "oracle"}); err == nil {
+		t.Error("expect not support the dialect error")
+	}
+	if err := ExecInsert[int](ctx, mock, db, 1, SQLConfig{Dialect: SQLite}); err == nil {
+		t.Error("expect not a struct error")
+	}
+	t.Logf("success:\n%s", lines[0])
+}
@@ -0,0 +1,48 @@
+package gomock
+
+import (
+	"context"
+	"reflect"
+)
+
+// Mocker is implemented by the type which mocks itself, eg: Money, Email or ID wrappers.
+// the field of the type is mocked without key tag or RegisterMock, the key tag takes precedence.
+// MockValue is called on a new value of the type, fl gives the tags of the field.
+type Mocker interface {
+	MockValue(ctx context.Context, fl FieldLevel) error
+}
+
+var mockerType = reflect.TypeOf((*Mocker)(nil)).Elem()
+
+// isMocker report whether the type or its ptr implements Mocker
+func isMocker(rt reflect.Type) bool {
+	if rt.Kind() == reflect.Pointer {
+		rt = rt.Elem()
+	}
+	return reflect.PointerTo(rt).Implements(mockerType)
+}
+
+// isMockerField report whether the untagged field is mocked by Mocker, the kinds not supported by the cache are skipped
+// like the other untagged fields
+func isMockerField(rs reflect.StructField) bool {
+	if !rs.IsExported() || !isMocker(rs.Type) {
+		return false
+	}
+	rt := rs.Type
+	if rt.Kind() == reflect.Pointer {
+		rt = rt.Elem()
+	}
+	_, ok := notSupportTypes[rt.Kind()]
+	return !ok
+}
+
+func mockMocker(ctx context.Context, fl FieldLevel) (reflect.Value, error) {
+	ptr := reflect.New(fl.GetType())
+	if err := ptr.Interface().(Mocker).MockValue(ctx, fl); err != nil {
+		return reflect.Value{}, err
+	}
+	if fl.IsPtr() {
+		return ptr, nil
+	}
+	return ptr.Elem(), nil
+}
@@ -0,0 +1,345 @@
+// Package mockserver serve the mocked json of the registered response types, for the front-end waiting for the back-end API.
+//
+//	srv := mockserver.New(gomock.New(), mockserver.WithSeedByPath(42))
+//	srv.GET("/users/:id", User{})
+//	srv.GET("/users", []User{}).Count(20)
+//	_ = http.ListenAndServe(":8080", srv)
+package mockserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pigfu/gomock"
+)
+
+const (
+	paramPrefix  = ":"
+	defaultCount = 10
+)
+
+type Option func(s *Server)
+
+// WithSeedByPath mock the same response for the same path and query, the seed is mixed with the path
+func WithSeedByPath(seed int64) Option {
+	return func(s *Server) {
+		s.seedByPath, s.seed = true, seed
+	}
+}
+
+// WithLatency delay every response by min plus a random duration less than jitter
+func WithLatency(min, jitter time.Duration) Option {
+	return func(s *Server) {
+		s.latency, s.jitter = min, jitter
+	}
+}
+
+// WithErrorRate respond the status instead of the mocked json by the rate in [0,1]
+func WithErrorRate(rate float64, status int) Option {
+	return func(s *Server) {
+		s.errorRate, s.errorStatus = rate, status
+	}
+}
+
+// Server is a http.Handler, it is usable with httptest.NewServer
+type Server struct {
+	mock        *gomock.Mock
+	lock        sync.RWMutex
+	routes      []*Route
+	seedByPath  bool
+	seed        int64
+	latency     time.Duration
+	jitter      time.Duration
+	errorRate   float64
+	errorStatus int
+}
+
+func New(m *gomock.Mock, opts ...Option) *Server {
+	s := &Server{mock: m, errorStatus: http.StatusInternalServerError}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Route is a registered route, the response is a struct, a struct ptr or a slice of them
+type Route struct {
+	method   string
+	segments []string
+	rt       reflect.Type //the response type
+	status   int
+	count    int //the length of the slice response
+}
+
+// Status set the status of the mocked response, default 200
+func (r *Route) Status(status int) *Route {
+	r.status = status
+	return r
+}
+
+// Count set the length of the slice response, default 10
+func (r *Route) Count(count int) *Route {
+	r.count = count
+	return r
+}
+
+func (s *Server) GET(pattern string, response any) *Route {
+	return s.Handle(http.MethodGet, pattern, response)
+}
+func (s *Server) POST(pattern string, response any) *Route {
+	return s.Handle(http.MethodPost, pattern, response)
+}
+func (s *Server) PUT(pattern string, response any) *Route {
+	return s.Handle(http.MethodPut, pattern, response)
+}
+func (s *Server) DELETE(pattern string, response any) *Route {
+	return s.Handle(http.MethodDelete, pattern, response)
+}
+
+// Handle register the response type of the method and the path pattern, the path params start with colon, eg: /users/:id.
+// the field of the response struct named as the param, by the field name or the json name, takes the param value.
+// it panics if the response is not a struct, a struct ptr or a slice of them
+func (s *Server) Handle(method, pattern string, response any) *Route {
+	rt := reflect.TypeOf(response)
+	if err := checkResponse(rt); err != nil {
+		panic(fmt.Sprintf("mockserver: %s %s,err:%v", method, pattern, err))
+	}
+	route := &Route{
+		method:   method,
+		segments: splitPath(pattern),
+		rt:       rt,
+		status:   http.StatusOK,
+		count:    defaultCount,
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.routes = append(s.routes, route)
+	return route
+}
+
+func checkResponse(rt reflect.Type) error {
+	if rt == nil {
+		return errors.New("nil response")
+	}
+	if rt.Kind() == reflect.Slice {
+		rt = rt.Elem()
+	}
+	if rt.Kind() == reflect.Pointer {
+		rt = rt.Elem()
+	}
+	if rt.Kind() != reflect.Struct {
+		return errors.New("not a struct, struct ptr or slice of them")
+	}
+	return nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	route, params, allowed := s.match(req.Method, req.URL.Path)
+	if route == nil {
+		if allowed {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	if s.seedByPath {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(req.Method + " " + req.URL.Path + "?" + req.URL.RawQuery))
+		r = rand.New(rand.NewSource(s.seed ^ int64(h.Sum64())))
+	}
+	if err := s.delay(req.Context(), r); err != nil {
+		return
+	}
+	if s.errorRate > 0 && r.Float64() < s.errorRate {
+		writeError(w, s.errorStatus, "injected error")
+		return
+	}
+	ctx := gomock.WithRand(req.Context(), r)
+	body, err := s.mockResponse(ctx, route, params)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(route.status)
+	_, _ = w.Write(body)
+}
+
+// match return the route and the path params, allowed reports whether the path matches with other method
+func (s *Server) match(method, path string) (*Route, map[string]string, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	var (
+		segments = splitPath(path)
+		allowed  bool
+	)
+	for _, route := range s.routes {
+		params, ok := route.match(segments)
+		if !ok {
+			continue
+		}
+		if route.method == method {
+			return route, params, true
+		}
+		allowed = true
+	}
+	return nil, nil, allowed
+}
+
+func (r *Route) match(segments []string) (map[string]string, bool) {
+	if len(segments) != len(r.segments) {
+		return nil, false
+	}
+	params := make(map[string]string)
+	for i, segment := range r.segments {
+		if strings.HasPrefix(segment, paramPrefix) {
+			params[segment[len(paramPrefix):]] = segments[i]
+			continue
+		}
+		if segment != segments[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+func (s *Server) delay(ctx context.Context, r *rand.Rand) error {
+	d := s.latency
+	if s.jitter > 0 {
+		d += time.Duration(r.Int63n(int64(s.jitter)))
+	}
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func (s *Server) mockResponse(ctx context.Context, route *Route, params map[string]string) ([]byte, error) {
+	if route.rt.Kind() != reflect.Slice {
+		value, err := s.mockOne(ctx, route.rt, params)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(value.Interface())
+	}
+	values := reflect.MakeSlice(route.rt, 0, route.count)
+	for i := 0; i < route.count; i++ {
+		value, err := s.mockOne(ctx, route.rt.Elem(), params)
+		if err != nil {
+			return nil, err
+		}
+		values = reflect.Append(values, value)
+	}
+	return json.Marshal(values.Interface())
+}
+
+// mockOne mock the struct or the struct ptr, the fields named as the path params take the param values
+func (s *Server) mockOne(ctx context.Context, rt reflect.Type, params map[string]string) (reflect.Value, error) {
+	st := rt
+	if st.Kind() == reflect.Pointer {
+		st = st.Elem()
+	}
+	ptr := reflect.New(st)
+	if err := s.mock.StructCtx(ctx, ptr.Interface()); err != nil {
+		return reflect.Value{}, err
+	}
+	for name, param := range params {
+		field, ok := paramField(st, name)
+		if !ok {
+			continue
+		}
+		setParam(ptr.Elem().FieldByIndex(field.Index), param)
+	}
+	if rt.Kind() == reflect.Pointer {
+		return ptr, nil
+	}
+	return ptr.Elem(), nil
+}
+
+// paramField find the field by the name or the json name, case-insensitive
+func paramField(rt reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		jsonName := strings.Split(field.Tag.Get("json"), ",")[0]
+		if strings.EqualFold(field.Name, name) || jsonName == name {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// setParam parse the param to the value of the field type, the ptr is followed. the invalid param is ignored,
+// the mocked value is kept
+func setParam(val reflect.Value, param string) bool {
+	if val.Kind() == reflect.Pointer {
+		ptr := reflect.New(val.Type().Elem())
+		if !setParam(ptr.Elem(), param) {
+			return false
+		}
+		val.Set(ptr)
+		return true
+	}
+	switch val.Kind() {
+	case reflect.String:
+		val.SetString(param)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v, err := strconv.ParseInt(param, 10, val.Type().Bits())
+		if err != nil {
+			return false
+		}
+		val.SetInt(v)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v, err := strconv.ParseUint(param, 10, val.Type().Bits())
+		if err != nil {
+			return false
+		}
+		val.SetUint(v)
+	case reflect.Float32, reflect.Float64:
+		v, err := strconv.ParseFloat(param, val.Type().Bits())
+		if err != nil {
+			return false
+		}
+		val.SetFloat(v)
+	case reflect.Bool:
+		v, err := strconv.ParseBool(param)
+		if err != nil {
+			return false
+		}
+		val.SetBool(v)
+	default:
+		return false
+	}
+	return true
+}
+
+func splitPath(path string) []string {
+	return strings.Split(strings.Trim(path, "/"), "/")
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": msg})
+}
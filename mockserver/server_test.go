@@ -0,0 +1,113 @@
+package mockserver
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pigfu/gomock"
+)
+
+type User struct {
+	Id    int64    `json:"id" mock:"key=integer,gte=1,lte=1000"`
+	Name  string   `json:"name" mock:"key=string,gte=3,lte=8"`
+	Email *string  `json:"email" mock:"key=email"`
+	Age   *int32   `json:"age" mock:"key=integer,gte=18,lte=60"`
+	Tags  []string `json:"tags" mock:"lte=3,into=1,key=string,options=vip new"`
+}
+
+func get(t *testing.T, url string) (int, []byte) {
+	t.Helper()
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode, body
+}
+
+func TestServer(t *testing.T) {
+	srv := New(gomock.New(), WithSeedByPath(42))
+	srv.GET("/users/:id", User{})
+	srv.GET("/users", []*User{}).Count(3)
+	srv.GET("/ages/:age", User{})
+	srv.POST("/users", User{}).Status(http.StatusCreated)
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	status, body := get(t, ts.URL+"/users/42")
+	var user User
+	if err := json.Unmarshal(body, &user); err != nil || status != http.StatusOK {
+		t.Fatal(status, string(body), err)
+	}
+	if user.Id != 42 || len(user.Name) < 3 || user.Email == nil {
+		t.Errorf("unexpected user: %s", body)
+	}
+	if _, again := get(t, ts.URL+"/users/42"); string(again) != string(body) {
+		t.Errorf("expect the same response by path:\n%s\n%s", body, again)
+	}
+	if _, other := get(t, ts.URL+"/users/43"); string(other) == string(body) {
+		t.Error("expect the different response by path")
+	}
+	status, body = get(t, ts.URL+"/ages/abc")
+	if err := json.Unmarshal(body, &user); err != nil || user.Age == nil || *user.Age < 18 || *user.Age > 60 {
+		t.Errorf("expect the invalid param is ignored: %s", body)
+	}
+	if _, body = get(t, ts.URL+"/ages/7"); json.Unmarshal(body, &user) != nil || user.Age == nil || *user.Age != 7 {
+		t.Errorf("expect the age param: %s", body)
+	}
+
+	status, body = get(t, ts.URL+"/users")
+	var users []*User
+	if err := json.Unmarshal(body, &users); err != nil || status != http.StatusOK || len(users) != 3 {
+		t.Fatal(status, string(body), err)
+	}
+	resp, err := http.Post(ts.URL+"/users", "application/json", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		t.Errorf("expect 201, got %d", resp.StatusCode)
+	}
+	if status, _ = get(t, ts.URL+"/orders"); status != http.StatusNotFound {
+		t.Errorf("expect 404, got %d", status)
+	}
+	req, _ := http.NewRequest(http.MethodDelete, ts.URL+"/users/1", nil)
+	if resp, err = http.DefaultClient.Do(req); err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("expect 405, got %d", resp.StatusCode)
+	}
+	t.Logf("success: %s", body)
+}
+
+func TestServerErrorAndLatency(t *testing.T) {
+	srv := New(gomock.New(), WithErrorRate(1, http.StatusServiceUnavailable), WithLatency(20*time.Millisecond, 0))
+	srv.GET("/users/:id", User{})
+	rec := httptest.NewRecorder()
+	start := time.Now()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/1", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("expect 503, got %d", rec.Code)
+	}
+	if time.Since(start) < 20*time.Millisecond {
+		t.Error("expect the latency")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expect panic for the invalid response")
+		}
+	}()
+	srv.GET("/count", 1)
+}
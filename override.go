@@ -0,0 +1,152 @@
+package gomock
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const (
+	pathSeparator = "."
+	pathWildcard  = "*" //match any index of slice, eg: Hobbies.*.Name
+)
+
+type overrideKey struct{}
+type sliceIndexKey struct{}
+
+// overrides is immutable, WithOverrideFunc copies it
+type overrides struct {
+	exact    map[string]MockFunc
+	patterns []string
+}
+
+// WithOverride set the value of the field path in this call, the slice elements are addressed by index or *,
+// eg: Hobby.Name, Hobbies.1.Name, Hobbies.*.Name. the value is converted to the field type if possible
+func WithOverride(ctx context.Context, path string, value any) context.Context {
+	return WithOverrideFunc(ctx, path, func(_ context.Context, fl FieldLevel) (reflect.Value, error) {
+		return overrideValue(fl, value)
+	})
+}
+
+// WithOverrideFunc mock the field path by fn instead of the field's mock function in this call, see WithOverride
+func WithOverrideFunc(ctx context.Context, path string, fn MockFunc) context.Context {
+	if fn == nil {
+		return ctx
+	}
+	prev, _ := ctx.Value(overrideKey{}).(*overrides)
+	next := &overrides{exact: make(map[string]MockFunc)}
+	if prev != nil {
+		for key, value := range prev.exact {
+			next.exact[key] = value
+		}
+		next.patterns = append(next.patterns, prev.patterns...)
+	}
+	if _, ok := next.exact[path]; !ok && strings.Contains(path, pathWildcard) {
+		next.patterns = append(next.patterns, path)
+	}
+	next.exact[path] = fn
+	return context.WithValue(ctx, overrideKey{}, next)
+}
+
+func hasOverride(ctx context.Context) bool {
+	_, ok := ctx.Value(overrideKey{}).(*overrides)
+	return ok
+}
+
+// withSliceIndex record the index of the slice element for the path of overrides
+func withSliceIndex(ctx context.Context, index int) context.Context {
+	prev, _ := ctx.Value(sliceIndexKey{}).([]int)
+	indexes := make([]int, len(prev), len(prev)+1)
+	copy(indexes, prev)
+	return context.WithValue(ctx, sliceIndexKey{}, append(indexes, index))
+}
+
+// fieldPath replace the index fillers of the alias with the indexes of the slice elements
+func fieldPath(ctx context.Context, fl FieldLevel) string {
+	indexes, _ := ctx.Value(sliceIndexKey{}).([]int)
+	if len(indexes) == 0 {
+		return fl.GetAlias()
+	}
+	segments := strings.Split(fl.GetAlias(), pathSeparator)
+	for i, j := 0, 0; i < len(segments) && j < len(indexes); i++ {
+		if segments[i] == "0" { //field name can not be a number
+			segments[i] = strconv.Itoa(indexes[j])
+			j++
+		}
+	}
+	return strings.Join(segments, pathSeparator)
+}
+
+func lookupOverride(ctx context.Context, fl FieldLevel) MockFunc {
+	ov, ok := ctx.Value(overrideKey{}).(*overrides)
+	if !ok || fl.GetAlias() == "" {
+		return nil
+	}
+	path := fieldPath(ctx, fl)
+	if fn, ok := ov.exact[path]; ok {
+		return fn
+	}
+	for _, pattern := range ov.patterns {
+		if matchPath(pattern, path) {
+			return ov.exact[pattern]
+		}
+	}
+	return nil
+}
+
+func matchPath(pattern, path string) bool {
+	patterns, segments := strings.Split(pattern, pathSeparator), strings.Split(path, pathSeparator)
+	if len(patterns) != len(segments) {
+		return false
+	}
+	for i := range patterns {
+		if patterns[i] != segments[i] && patterns[i] != pathWildcard {
+			return false
+		}
+	}
+	return true
+}
+
+// overrideValue convert the value to the type of the field
+func overrideValue(fl FieldLevel, value any) (reflect.Value, error) {
+	target := fl.GetType()
+	if fl.IsPtr() {
+		target = reflect.PointerTo(target)
+	}
+	rv := reflect.ValueOf(value)
+	if !rv.IsValid() {
+		return reflect.Zero(target), nil
+	}
+	if rv.Type().AssignableTo(target) {
+		return rv, nil
+	}
+	if rv.Kind() == reflect.Pointer && rv.Type().Elem().Kind() == fl.GetKind() {
+		if rv.IsNil() {
+			return reflect.Zero(target), nil
+		}
+		rv = rv.Elem()
+	}
+	if !convertible(rv.Type(), fl.GetType()) {
+		return reflect.Value{}, fmt.Errorf("field:%s,err:can not use %T as %s", fl.GetAlias(), value, target)
+	}
+	rv = rv.Convert(fl.GetType())
+	if fl.IsPtr() {
+		ptr := reflect.New(fl.GetType())
+		ptr.Elem().Set(rv)
+		return ptr, nil
+	}
+	return rv, nil
+}
+
+// convertible avoid the conversions between number and string, eg: 65 to "A"
+func convertible(from, to reflect.Type) bool {
+	if !from.ConvertibleTo(to) {
+		return false
+	}
+	if from.Kind() == to.Kind() {
+		return true
+	}
+	return (isIntegerKind(from) || isDecimalKind(from)) && (isIntegerKind(to) || isDecimalKind(to))
+}
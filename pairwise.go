@@ -0,0 +1,186 @@
+package gomock
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// pairField is a field with options, the values are sorted by weight
+type pairField struct {
+	path   string //the override path, the slice elements are addressed by *
+	values []any
+}
+
+// pair is the value a of the field i and the value b of the field j, i < j
+type pair struct {
+	i, a, j, b int
+}
+
+// Pairwise mock the fewest values of T that cover all pairs of the option values across the fields with options,
+// the option with larger weight is covered first. the other fields are mocked as usual, T is a struct or a struct ptr
+func Pairwise[T any](ctx context.Context, m *Mock) ([]T, error) {
+	var zero T
+	rt, _ := m.Indirect(reflect.TypeOf(&zero).Elem())
+	if rt.Kind() != reflect.Struct {
+		return nil, errors.New("not a struct or struct ptr")
+	}
+	fl, err := m.genCache(ctx, reflect.New(rt))
+	if err != nil {
+		return nil, err
+	}
+	fields := pairFields(fl, nil)
+	values := make([]T, 0)
+	for _, combination := range pairwise(fields) {
+		cctx := ctx
+		for i, field := range fields {
+			cctx = WithOverride(cctx, field.path, field.values[combination[i]])
+		}
+		value, err := makeOne[T](cctx, m)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// pairFields collect the fields whose value is selected from options, see generateString
+func pairFields(fl FieldLevel, fields []pairField) []pairField {
+	for _, child := range fl.GetChildren() {
+		fields = pairFields(child, fields)
+	}
+	tm := fl.GetTags()
+	if fl.GetAlias() == "" || !tm.Key(MockOptions).Exists() || tm.Key(MockEqual).Exists() {
+		return fields
+	}
+	var values []any
+	switch {
+	case fl.GetKind() == reflect.String:
+		for _, option := range tm.Key(MockOptions).GetStrSet() {
+			values = append(values, option)
+		}
+	case isIntegerKind(fl.GetType()):
+		for _, option := range tm.Key(MockOptions).GetInt64Set() {
+			values = append(values, option)
+		}
+	case isDecimalKind(fl.GetType()):
+		for _, option := range tm.Key(MockOptions).GetFloat64Set() {
+			values = append(values, option)
+		}
+	}
+	if len(values) == 0 {
+		return fields
+	}
+	weights := tm.Key(MockWeights).GetInt64Set()
+	weight := func(i int) int64 {
+		if len(weights) == 0 {
+			return 1
+		}
+		if i < len(weights) {
+			return weights[i]
+		}
+		return 0
+	}
+	indexes := make([]int, len(values))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	sort.SliceStable(indexes, func(x, y int) bool { return weight(indexes[x]) > weight(indexes[y]) })
+	sorted := make([]any, 0, len(values))
+	for _, i := range indexes {
+		sorted = append(sorted, values[i])
+	}
+	segments := strings.Split(fl.GetAlias(), pathSeparator)
+	for i := range segments {
+		if segments[i] == "0" { //the slice element
+			segments[i] = pathWildcard
+		}
+	}
+	return append(fields, pairField{path: strings.Join(segments, pathSeparator), values: sorted})
+}
+
+// pairwise return the combinations of the value indexes covering all pairs, greedy and deterministic:
+// every combination starts with the first uncovered pair and the other fields take the value covering most new pairs
+func pairwise(fields []pairField) [][]int {
+	switch len(fields) {
+	case 0:
+		return [][]int{nil}
+	case 1:
+		combinations := make([][]int, 0, len(fields[0].values))
+		for a := range fields[0].values {
+			combinations = append(combinations, []int{a})
+		}
+		return combinations
+	}
+	var (
+		uncovered    = make(map[pair]struct{})
+		order        []pair
+		combinations [][]int
+	)
+	for i := range fields {
+		for j := i + 1; j < len(fields); j++ {
+			for a := range fields[i].values {
+				for b := range fields[j].values {
+					p := pair{i: i, a: a, j: j, b: b}
+					uncovered[p] = struct{}{}
+					order = append(order, p)
+				}
+			}
+		}
+	}
+	sort.SliceStable(order, func(x, y int) bool { //the values with larger weight first
+		return order[x].a+order[x].b < order[y].a+order[y].b
+	})
+	for len(uncovered) > 0 {
+		var first pair
+		for _, p := range order {
+			if _, ok := uncovered[p]; ok {
+				first = p
+				break
+			}
+		}
+		combination := make([]int, len(fields))
+		for k := range combination {
+			combination[k] = -1
+		}
+		combination[first.i], combination[first.j] = first.a, first.b
+		for k := range fields {
+			if combination[k] >= 0 {
+				continue
+			}
+			best, bestCount := 0, -1
+			for c := range fields[k].values {
+				count := 0
+				for l := range fields {
+					if combination[l] < 0 {
+						continue
+					}
+					if _, ok := uncovered[newPair(l, combination[l], k, c)]; ok {
+						count++
+					}
+				}
+				if count > bestCount {
+					best, bestCount = c, count
+				}
+			}
+			combination[k] = best
+		}
+		for i := range fields {
+			for j := i + 1; j < len(fields); j++ {
+				delete(uncovered, pair{i: i, a: combination[i], j: j, b: combination[j]})
+			}
+		}
+		combinations = append(combinations, combination)
+	}
+	return combinations
+}
+
+func newPair(i, a, j, b int) pair {
+	if i > j {
+		return pair{i: j, a: b, j: i, b: a}
+	}
+	return pair{i: i, a: a, j: j, b: b}
+}
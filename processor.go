@@ -8,6 +8,7 @@ import (
 	"math"
 	"math/rand"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -51,24 +52,27 @@ var (
 type MockFunc func(context.Context, FieldLevel) (reflect.Value, error)
 
 // make slice
-func mockSlice(_ context.Context, fl FieldLevel) (reflect.Value, error) {
-	rt, tm := fl.GetType(), fl.GetTags()
+func mockSlice(ctx context.Context, fl FieldLevel) (reflect.Value, error) {
+	n := sliceLength(ctx, fl)
+	return reflect.MakeSlice(fl.GetType(), n, n), nil
+}
+
+func sliceLength(ctx context.Context, fl FieldLevel) int {
+	tm := fl.GetTags()
 	eq := tm.Key(MockEqual).GetInt()
 	if eq > 0 {
-		return reflect.MakeSlice(rt, eq, eq), nil
+		TraceBranch(ctx, BranchEqual)
+		return eq
 	}
+	TraceBranch(ctx, BranchRange)
 	gte, gteExists := makeGteVal(reflect.Uint8, tm.Key(MockGt).GetInt(), tm.Key(MockGte).GetInt(),
 		tm.Key(MockGt).Exists(), tm.Key(MockGte).Exists())
 	lt, ltExists := makeLtVal(reflect.Uint8, tm.Key(MockLt).GetInt(), tm.Key(MockLte).GetInt(),
 		tm.Key(MockLt).Exists(), tm.Key(MockLte).Exists())
 	if !gteExists && !ltExists || gte < 0 || lt <= 0 || gte >= lt {
-		return reflect.MakeSlice(rt, 0, 0), nil
-	}
-	eq = gte
-	if gte < lt {
-		eq += rand.Intn(lt - gte)
+		return 0
 	}
-	return reflect.MakeSlice(rt, eq, eq), nil
+	return randLength(ctx, gte, lt)
 }
 
 // make struct
@@ -77,11 +81,11 @@ func mockStruct(_ context.Context, fl FieldLevel) (reflect.Value, error) {
 }
 
 // mock random string
-func mockString(_ context.Context, fl FieldLevel) (reflect.Value, error) {
+func mockString(ctx context.Context, fl FieldLevel) (reflect.Value, error) {
 	if fl.GetKind() != reflect.String {
 		return reflect.New(fl.GetType()), errors.New("only support the type string")
 	}
-	val, err := generateString(fl)
+	val, err := generateString(ctx, fl)
 	if err != nil {
 		return reflect.Value{}, err
 	}
@@ -91,23 +95,27 @@ func mockString(_ context.Context, fl FieldLevel) (reflect.Value, error) {
 	return reflect.ValueOf(val), nil
 }
 
-func generateString(fl FieldLevel) (string, error) {
+func generateString(ctx context.Context, fl FieldLevel) (string, error) {
 	tm := fl.GetTags()
 	eqVal := tm.Key(MockEqual).GetStr()
 	if eqVal != "" {
+		TraceBranch(ctx, BranchEqual)
 		return eqVal, nil
 	}
 	if tm.Key(MockOptions).Exists() {
-		return selectOne(fl, tm.Key(MockOptions).GetStrSet()), nil
+		TraceBranch(ctx, BranchOptions)
+		return selectOne(ctx, fl, tm.Key(MockOptions).GetStrSet()), nil
 	}
 	if tm.Key(MockRegExp).Exists() {
-		return regen.Generate(tm.Key(MockRegExp).GetStr())
+		TraceBranch(ctx, BranchRegExp)
+		return regen.GenerateRand(tm.Key(MockRegExp).GetStr(), GetRand(ctx))
 	}
-	return rangeString(fl), nil
+	TraceBranch(ctx, BranchRange)
+	return rangeString(ctx, fl), nil
 }
 
-func rangeString(fl FieldLevel) string {
-	tm := fl.GetTags()
+func rangeString(ctx context.Context, fl FieldLevel) string {
+	r, tm := GetRand(ctx), fl.GetTags()
 	gte, gteExists := makeGteVal(reflect.Uint8, tm.Key(MockGt).GetInt(), tm.Key(MockGte).GetInt(),
 		tm.Key(MockGt).Exists(), tm.Key(MockGte).Exists())
 	lt, ltExists := makeLtVal(reflect.Uint8, tm.Key(MockLt).GetInt(), tm.Key(MockLte).GetInt(),
@@ -115,21 +123,29 @@ func rangeString(fl FieldLevel) string {
 	if !gteExists && !ltExists || gte < 0 || lt <= 0 || gte >= lt {
 		return ""
 	}
-	n := gte
-	if gte < lt {
-		n += rand.Intn(lt - gte)
-	}
+	n := randLength(ctx, gte, lt)
 	str := &strings.Builder{}
 	str.Grow(n)
 	for i := 0; i < n; i++ {
-		str.WriteByte(letters[rand.Int63()%int64(len(letters))])
+		str.WriteByte(letters[r.Int63()%int64(len(letters))])
 	}
 	return str.String()
 }
 
+// return the length one of [gte,lt), lt is limited by the size of the call
+func randLength(ctx context.Context, gte, lt int) int {
+	if size, ok := getSize(ctx); ok && size+1 < lt {
+		lt = maxFunc(gte+1, size+1)
+	}
+	if b, ok := getBoundary(ctx); ok {
+		return int(pickBoundary(ctx, b, rangeBoundaries(int64(gte), int64(lt))))
+	}
+	return gte + GetRand(ctx).Intn(lt-gte)
+}
+
 // mock integer. for int,int8,int64...
-func mockInteger(_ context.Context, fl FieldLevel) (reflect.Value, error) {
-	val, err := generateInteger(fl)
+func mockInteger(ctx context.Context, fl FieldLevel) (reflect.Value, error) {
+	val, err := generateInteger(ctx, fl)
 	if err != nil {
 		return reflect.Value{}, err
 	}
@@ -158,29 +174,33 @@ func mockInteger(_ context.Context, fl FieldLevel) (reflect.Value, error) {
 	return reflect.New(fl.GetType()), fmt.Errorf("not support the type %s", fl.GetKind())
 }
 
-func generateInteger(fl FieldLevel) (int64, error) {
+func generateInteger(ctx context.Context, fl FieldLevel) (int64, error) {
 	tm := fl.GetTags()
 	if tm.Key(MockEqual).Exists() {
+		TraceBranch(ctx, BranchEqual)
 		return tm.Key(MockEqual).GetInt64(), nil
 	}
 	if tm.Key(MockOptions).Exists() {
-		return selectOne(fl, tm.Key(MockOptions).GetInt64Set()), nil
+		TraceBranch(ctx, BranchOptions)
+		return selectOne(ctx, fl, tm.Key(MockOptions).GetInt64Set()), nil
 	}
 	if tm.Key(MockRegExp).Exists() {
-		return regenInteger(tm.Key(MockRegExp).GetStr())
+		TraceBranch(ctx, BranchRegExp)
+		return regenInteger(ctx, tm.Key(MockRegExp).GetStr())
 	}
-	return rangeInteger(fl), nil
+	TraceBranch(ctx, BranchRange)
+	return rangeInteger(ctx, fl), nil
 }
 
-func regenInteger(pattern string) (int64, error) {
-	str, err := regen.Generate(pattern)
+func regenInteger(ctx context.Context, pattern string) (int64, error) {
+	str, err := regen.GenerateRand(pattern, GetRand(ctx))
 	if err != nil {
 		return 0, err
 	}
 	return strconv.ParseInt(str, 10, 64)
 }
 
-func rangeInteger(fl FieldLevel) int64 {
+func rangeInteger(ctx context.Context, fl FieldLevel) int64 {
 	tm := fl.GetTags()
 	gte, gteExists := makeGteVal(fl.GetKind(), tm.Key(MockGt).GetInt64(), tm.Key(MockGte).GetInt64(),
 		tm.Key(MockGt).Exists(), tm.Key(MockGte).Exists())
@@ -189,22 +209,25 @@ func rangeInteger(fl FieldLevel) int64 {
 	if !gteExists && !ltExists || gte >= lt {
 		return 0
 	}
-	return randRangeInt64(gte, lt)
+	if b, ok := getBoundary(ctx); ok {
+		return pickBoundary(ctx, b, rangeBoundaries(gte, lt))
+	}
+	return randRangeInt64(GetRand(ctx), gte, lt)
 }
 
 // return value one of [gte,lt)
-func randRangeInt64(gte, lt int64) int64 {
+func randRangeInt64(r *rand.Rand, gte, lt int64) int64 {
 	if gte >= 0 {
-		return gte + rand.Int63n(lt-gte)
+		return gte + r.Int63n(lt-gte)
 	}
 	if gte < 0 && lt <= 0 {
-		return -(1 - lt + rand.Int63n(lt-gte))
+		return -(1 - lt + r.Int63n(lt-gte))
 	}
-	point := rand.Int63n(lt - gte)
+	point := r.Int63n(lt - gte)
 	if point < lt {
-		return randRangeInt64(0, lt)
+		return randRangeInt64(r, 0, lt)
 	}
-	return randRangeInt64(gte, 0)
+	return randRangeInt64(r, gte, 0)
 }
 
 func int64ToInt(fl FieldLevel, val int64) reflect.Value {
@@ -286,12 +309,15 @@ func int64ToUint64(fl FieldLevel, val int64) reflect.Value {
 	return reflect.ValueOf(nv)
 }
 
-func selectOne[T string | int64 | float64](fl FieldLevel, options []T) T {
+func selectOne[T string | int64 | float64](ctx context.Context, fl FieldLevel, options []T) T {
+	if b, ok := getBoundary(ctx); ok && len(options) > 0 {
+		return pickBoundary(ctx, b, []T{options[0], options[len(options)-1]})
+	}
 	var (
 		weights = fl.GetTags().Key(MockWeights).GetInt64Set()
 	)
 	var value T
-	placement, sum := rand.Int63n(sumWeights(weights, len(options))), int64(0)
+	placement, sum := GetRand(ctx).Int63n(sumWeights(weights, len(options))), int64(0)
 	for i, option := range options {
 		if len(weights) > 0 {
 			sum = weights[i]
@@ -318,8 +344,8 @@ func sumWeights(weights []int64, number int) int64 {
 }
 
 // mock decimal. for float32,float64
-func mockDecimal(_ context.Context, fl FieldLevel) (reflect.Value, error) {
-	val, err := generateDecimal(fl)
+func mockDecimal(ctx context.Context, fl FieldLevel) (reflect.Value, error) {
+	val, err := generateDecimal(ctx, fl)
 	if err != nil {
 		return reflect.Value{}, err
 	}
@@ -344,29 +370,33 @@ func float64ToFloat32(fl FieldLevel, val float64) reflect.Value {
 	}
 	return reflect.ValueOf(nv)
 }
-func generateDecimal(fl FieldLevel) (float64, error) {
+func generateDecimal(ctx context.Context, fl FieldLevel) (float64, error) {
 	tm := fl.GetTags()
 	if tm.Key(MockEqual).Exists() {
+		TraceBranch(ctx, BranchEqual)
 		return tm.Key(MockEqual).GetFloat64(), nil
 	}
 	if tm.Key(MockOptions).Exists() {
-		return selectOne(fl, tm.Key(MockOptions).GetFloat64Set()), nil
+		TraceBranch(ctx, BranchOptions)
+		return selectOne(ctx, fl, tm.Key(MockOptions).GetFloat64Set()), nil
 	}
 	if tm.Key(MockRegExp).Exists() {
-		return regenDecimal(tm.Key(MockRegExp).GetStr())
+		TraceBranch(ctx, BranchRegExp)
+		return regenDecimal(ctx, tm.Key(MockRegExp).GetStr())
 	}
-	return rangeDecimal(fl), nil
+	TraceBranch(ctx, BranchRange)
+	return rangeDecimal(ctx, fl), nil
 }
 
-func regenDecimal(pattern string) (float64, error) {
-	str, err := regen.Generate(pattern)
+func regenDecimal(ctx context.Context, pattern string) (float64, error) {
+	str, err := regen.GenerateRand(pattern, GetRand(ctx))
 	if err != nil {
 		return 0, err
 	}
 	return strconv.ParseFloat(str, 64)
 }
 
-func rangeDecimal(fl FieldLevel) float64 {
+func rangeDecimal(ctx context.Context, fl FieldLevel) float64 {
 	tm := fl.GetTags()
 	conversion := decimalConversion(tm)
 	gte, gteExists := makeGteVal(fl.GetKind(), int64(tm.Key(MockGt).GetFloat64()*conversion),
@@ -376,7 +406,10 @@ func rangeDecimal(fl FieldLevel) float64 {
 	if !gteExists && !ltExists || gte >= lt {
 		return 0
 	}
-	return float64(randRangeInt64(gte, lt)) / conversion
+	if b, ok := getBoundary(ctx); ok {
+		return float64(pickBoundary(ctx, b, rangeBoundaries(gte, lt))) / conversion
+	}
+	return float64(randRangeInt64(GetRand(ctx), gte, lt)) / conversion
 }
 func decimalConversion(tm TagLevelMap) float64 {
 	conversion := maxFunc(numberOfDecimal(tm.Key(MockGt).GetStr()), numberOfDecimal(tm.Key(MockGte).GetStr()))
@@ -384,24 +417,25 @@ func decimalConversion(tm TagLevelMap) float64 {
 	return maxFunc(conversion, numberOfDecimal(tm.Key(MockLte).GetStr()))
 }
 func numberOfDecimal(value string) float64 {
-	values := strings.SplitN(value, ".", 2)
-	if len(values) == 1 {
+	_, fraction, ok := strings.Cut(value, ".")
+	if !ok {
 		return 1
 	}
-	return math.Pow(10, float64(len(values[1])))
+	return math.Pow(10, float64(len(fraction)))
 }
 
 // make mobile phone
-func mockMobilePhone(_ context.Context, fl FieldLevel) (reflect.Value, error) {
+func mockMobilePhone(ctx context.Context, fl FieldLevel) (reflect.Value, error) {
 	if fl.GetKind() != reflect.String {
 		return reflect.New(fl.GetType()), errors.New("only support the type string")
 	}
-	prefix := mobilePhonePrefix[rand.Intn(len(mobilePhonePrefix))]
+	r := GetRand(ctx)
+	prefix := mobilePhonePrefix[r.Intn(len(mobilePhonePrefix))]
 	phone := &strings.Builder{}
 	phone.Grow(mobilePhoneLen)
 	phone.WriteString(prefix)
 	for i := 0; i < mobilePhoneLen-len(prefix); i++ {
-		phone.WriteString(strconv.Itoa(rand.Intn(10)))
+		phone.WriteString(strconv.Itoa(r.Intn(10)))
 	}
 	phoneStr := phone.String()
 	if fl.IsPtr() {
@@ -409,16 +443,17 @@ func mockMobilePhone(_ context.Context, fl FieldLevel) (reflect.Value, error) {
 	}
 	return reflect.ValueOf(phoneStr), nil
 }
-func mockEmail(_ context.Context, fl FieldLevel) (reflect.Value, error) {
+func mockEmail(ctx context.Context, fl FieldLevel) (reflect.Value, error) {
 	if fl.GetKind() != reflect.String {
 		return reflect.New(fl.GetType()), errors.New("only support the type string")
 	}
-	postfix := emailPostfix[rand.Intn(len(emailPostfix))]
-	emailLen := 7 + rand.Intn(6)
+	r := GetRand(ctx)
+	postfix := emailPostfix[r.Intn(len(emailPostfix))]
+	emailLen := 7 + r.Intn(6)
 	email := &strings.Builder{}
 	email.Grow(emailLen)
 	for i := 0; i < emailLen; i++ {
-		email.WriteByte(letters[rand.Int63()%int64(len(letters))])
+		email.WriteByte(letters[r.Int63()%int64(len(letters))])
 	}
 	email.WriteString(postfix)
 	emailStr := email.String()
@@ -428,7 +463,7 @@ func mockEmail(_ context.Context, fl FieldLevel) (reflect.Value, error) {
 	return reflect.ValueOf(emailStr), nil
 }
 
-func mockAddress(_ context.Context, fl FieldLevel) (reflect.Value, error) {
+func mockAddress(ctx context.Context, fl FieldLevel) (reflect.Value, error) {
 	if fl.GetKind() != reflect.String {
 		return reflect.New(fl.GetType()), errors.New("only support the type string")
 	}
@@ -436,9 +471,10 @@ func mockAddress(_ context.Context, fl FieldLevel) (reflect.Value, error) {
 		return reflect.ValueOf(""), nil
 	}
 	result := &strings.Builder{}
-	provinceVal := randProvince(area)
-	cityVal := randCity(area[provinceVal])
-	countyVal := randCountry(area[provinceVal][cityVal])
+	r := GetRand(ctx)
+	provinceVal := randRegion(r, areaRegions)
+	cityVal := randRegion(r, provinceVal.children)
+	countyVal := randRegion(r, cityVal.children)
 
 	types := fl.GetTags().Key(MockAddress).GetStrSet()
 	for _, ty := range types {
@@ -447,11 +483,11 @@ func mockAddress(_ context.Context, fl FieldLevel) (reflect.Value, error) {
 		}
 		switch ty {
 		case province:
-			result.WriteString(provinceVal)
+			result.WriteString(provinceVal.name)
 		case city:
-			result.WriteString(cityVal)
+			result.WriteString(cityVal.name)
 		case county:
-			result.WriteString(countyVal)
+			result.WriteString(countyVal.name)
 		}
 	}
 	addrStr := result.String()
@@ -460,25 +496,47 @@ func mockAddress(_ context.Context, fl FieldLevel) (reflect.Value, error) {
 	}
 	return reflect.ValueOf(addrStr), nil
 }
-func randProvince(provinceMap map[string]map[string]map[string]struct{}) string {
-	provinces := mapToSlice(provinceMap, func(key string,
-		_ map[string]map[string]struct{}) string {
-		return key
-	})
-	return provinces[rand.Int63()%int64(len(provinces))]
+
+// areaRegions is the area sorted once, the same random source gives the same address
+var areaRegions = sortArea()
+
+// areaRegion is a province, city or county, the children are sorted by name
+type areaRegion struct {
+	name     string
+	children []*areaRegion
 }
 
-func randCity(cityMap map[string]map[string]struct{}) string {
-	cities := mapToSlice(cityMap, func(key string, _ map[string]struct{}) string {
-		return key
-	})
-	return cities[rand.Int63()%int64(len(cities))]
+func sortArea() []*areaRegion {
+	provinces := make([]*areaRegion, 0, len(area))
+	for province, cities := range area {
+		p := &areaRegion{name: province, children: make([]*areaRegion, 0, len(cities))}
+		for city, counties := range cities {
+			c := &areaRegion{name: city, children: make([]*areaRegion, 0, len(counties))}
+			for county := range counties {
+				c.children = append(c.children, &areaRegion{name: county})
+			}
+			sortRegions(c.children)
+			p.children = append(p.children, c)
+		}
+		sortRegions(p.children)
+		provinces = append(provinces, p)
+	}
+	return sortRegions(provinces)
 }
-func randCountry(countryMap map[string]struct{}) string {
-	countries := mapToSlice(countryMap, func(key string, _ struct{}) string {
-		return key
+
+func sortRegions(regions []*areaRegion) []*areaRegion {
+	sort.Slice(regions, func(i, j int) bool {
+		return regions[i].name < regions[j].name
 	})
-	return countries[rand.Int63()%int64(len(countries))]
+	return regions
+}
+
+// randRegion return a random region, the empty region if there is none, eg: some cities have no county
+func randRegion(r *rand.Rand, regions []*areaRegion) *areaRegion {
+	if len(regions) == 0 {
+		return &areaRegion{}
+	}
+	return regions[r.Int63()%int64(len(regions))]
 }
 
 func mockTime(_ context.Context, fl FieldLevel) (reflect.Value, error) {
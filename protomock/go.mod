@@ -0,0 +1,13 @@
+module github.com/pigfu/gomock/protomock
+
+go 1.20
+
+require (
+	github.com/pigfu/gomock v0.1.0
+	google.golang.org/protobuf v1.34.2
+)
+
+require (
+	github.com/google/go-cmp v0.6.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
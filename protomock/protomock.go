@@ -0,0 +1,404 @@
+// Package protomock mock the protobuf messages through protoreflect, the internal fields of the generated structs are not touched.
+// the oneofs take one of their fields, the enums take the valid numbers, the repeated and map fields, the well-known types
+// Timestamp, Duration and the wrappers are supported. the rules are the mock tags from the tag config of gomock.Mock,
+// keyed by the go type and field name as usual, eg: "github.com/org/app/pb.User.Email", or the proto full name,
+// eg: "example.v1.User.email", or from a string field option, see WithTagExtension.
+package protomock
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/pigfu/gomock"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+const (
+	defaultMaxDepth = 5
+
+	defaultStringTag  = "key=string,gte=1,lte=10"
+	defaultIntegerTag = "key=integer,gte=0,lte=1000"
+	defaultDecimalTag = "key=decimal,gte=0,lte=1000.00"
+
+	timestampName = "google.protobuf.Timestamp"
+	durationName  = "google.protobuf.Duration"
+	wrapperPrefix = "google.protobuf."
+	wrapperSuffix = "Value"
+	wrapperField  = "value"
+
+	maxTimestampAge = 365 * 24 * time.Hour
+	maxDuration     = time.Hour
+)
+
+var (
+	lengthType  = reflect.TypeOf([]struct{}{})
+	stringType  = reflect.TypeOf("")
+	int32Type   = reflect.TypeOf(int32(0))
+	int64Type   = reflect.TypeOf(int64(0))
+	uint32Type  = reflect.TypeOf(uint32(0))
+	uint64Type  = reflect.TypeOf(uint64(0))
+	float32Type = reflect.TypeOf(float32(0))
+	float64Type = reflect.TypeOf(float64(0))
+	dynamicType = reflect.TypeOf(&dynamicpb.Message{})
+)
+
+type Option func(pm *Mock)
+
+// WithTagExtension read the mock tag from the string extension of google.protobuf.FieldOptions, eg:
+//
+//	extend google.protobuf.FieldOptions { string mock = 50001; }
+//	string email = 1 [(mock) = "key=email"];
+func WithTagExtension(xt protoreflect.ExtensionType) Option {
+	return func(pm *Mock) {
+		pm.tagExt = xt
+	}
+}
+
+// WithMaxDepth limit the depth of the nested messages, the recursive message stops here, default 5
+func WithMaxDepth(depth int) Option {
+	return func(pm *Mock) {
+		pm.maxDepth = depth
+	}
+}
+
+type Mock struct {
+	m        *gomock.Mock
+	tagExt   protoreflect.ExtensionType
+	maxDepth int
+}
+
+func New(m *gomock.Mock, opts ...Option) *Mock {
+	pm := &Mock{m: m, maxDepth: defaultMaxDepth}
+	for _, opt := range opts {
+		opt(pm)
+	}
+	return pm
+}
+
+// Message mock the message, the random source is given by gomock.WithRand
+func (pm *Mock) Message(ctx context.Context, msg proto.Message) error {
+	return pm.mockMessage(ctx, msg.ProtoReflect(), 0)
+}
+
+func (pm *Mock) mockMessage(ctx context.Context, msg protoreflect.Message, depth int) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	md := msg.Descriptor()
+	switch name := string(md.FullName()); {
+	case name == timestampName:
+		age := gomock.GetRand(ctx).Int63n(int64(maxTimestampAge))
+		return setTime(msg, time.Now().Add(-time.Duration(age)).Truncate(time.Millisecond))
+	case name == durationName:
+		d := time.Duration(gomock.GetRand(ctx).Int63n(int64(maxDuration))).Truncate(time.Millisecond)
+		return setDuration(msg, d)
+	}
+	var (
+		fields = md.Fields()
+		oneofs = make(map[protoreflect.FullName]bool)
+		goType = goStruct(msg)
+	)
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if od := fd.ContainingOneof(); od != nil && !od.IsSynthetic() { //take one of the fields
+			if oneofs[od.FullName()] {
+				continue
+			}
+			oneofs[od.FullName()] = true
+			fd = od.Fields().Get(gomock.GetRand(ctx).Intn(od.Fields().Len()))
+		}
+		tag := pm.fieldTag(goType, fd)
+		if hasTag(tag, gomock.MockSkip) {
+			continue
+		}
+		if err := pm.mockField(ctx, msg, fd, tag, depth); err != nil {
+			return fmt.Errorf("field:%s,err:%v", fd.FullName(), err)
+		}
+	}
+	return nil
+}
+
+func (pm *Mock) mockField(ctx context.Context, msg protoreflect.Message, fd protoreflect.FieldDescriptor,
+	tag string, depth int) error {
+	listTag, elemTag := splitInto(tag)
+	switch {
+	case fd.IsList():
+		n, err := pm.length(ctx, listTag)
+		if err != nil {
+			return err
+		}
+		list := msg.Mutable(fd).List()
+		for i := 0; i < n; i++ {
+			value, err := pm.mockValue(ctx, fd, list.NewElement, elemTag, depth)
+			if err != nil {
+				return err
+			}
+			if value.IsValid() {
+				list.Append(value)
+			}
+		}
+	case fd.IsMap():
+		n, err := pm.length(ctx, listTag)
+		if err != nil {
+			return err
+		}
+		mp := msg.Mutable(fd).Map()
+		for i := 0; i < n; i++ {
+			key, err := pm.scalar(ctx, fd.MapKey(), "")
+			if err != nil {
+				return err
+			}
+			value, err := pm.mockValue(ctx, fd.MapValue(), mp.NewValue, elemTag, depth)
+			if err != nil {
+				return err
+			}
+			if value.IsValid() {
+				mp.Set(key.MapKey(), value)
+			}
+		}
+	default:
+		value, err := pm.mockValue(ctx, fd, func() protoreflect.Value { return msg.NewField(fd) }, tag, depth)
+		if err != nil || !value.IsValid() {
+			return err
+		}
+		msg.Set(fd, value)
+	}
+	return nil
+}
+
+// mockValue mock the single value of the field, the message is created by newValue. the invalid value is ignored
+func (pm *Mock) mockValue(ctx context.Context, fd protoreflect.FieldDescriptor, newValue func() protoreflect.Value,
+	tag string, depth int) (protoreflect.Value, error) {
+	if fd.Kind() != protoreflect.MessageKind && fd.Kind() != protoreflect.GroupKind {
+		return pm.scalar(ctx, fd, tag)
+	}
+	if depth >= pm.maxDepth {
+		return protoreflect.Value{}, nil
+	}
+	value := newValue()
+	msg := value.Message()
+	if inner := wrapped(msg.Descriptor()); inner != nil { //the tag is for the wrapped value
+		v, err := pm.scalar(ctx, inner, tag)
+		if err != nil {
+			return protoreflect.Value{}, err
+		}
+		msg.Set(inner, v)
+		return value, nil
+	}
+	return value, pm.mockMessage(ctx, msg, depth+1)
+}
+
+// enum keep the mocked number if it is declared, or take one of the declared numbers following the tag, eg: gte=0,lte=100
+func (pm *Mock) enum(ctx context.Context, fd protoreflect.FieldDescriptor, n protoreflect.EnumNumber, tag string) (protoreflect.Value, error) {
+	values := fd.Enum().Values()
+	if values.ByNumber(n) != nil {
+		return protoreflect.ValueOfEnum(n), nil
+	}
+	numbers := make([]protoreflect.EnumNumber, 0, values.Len())
+	for i := 0; i < values.Len(); i++ {
+		if pm.m.ValidateTag(reflect.ValueOf(int32(values.Get(i).Number())), tag) == nil {
+			numbers = append(numbers, values.Get(i).Number())
+		}
+	}
+	if len(numbers) == 0 {
+		return protoreflect.Value{}, fmt.Errorf("no declared enum number follows the tag:%s", tag)
+	}
+	return protoreflect.ValueOfEnum(numbers[gomock.GetRand(ctx).Intn(len(numbers))]), nil
+}
+
+// scalar mock the value of the scalar or enum field by the mock tag, the default tag is used if it is empty
+func (pm *Mock) scalar(ctx context.Context, fd protoreflect.FieldDescriptor, tag string) (protoreflect.Value, error) {
+	r := gomock.GetRand(ctx)
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return protoreflect.ValueOfBool(r.Intn(2) == 1), nil
+	case protoreflect.EnumKind:
+		if tag == "" {
+			values := fd.Enum().Values()
+			return protoreflect.ValueOfEnum(values.Get(r.Intn(values.Len())).Number()), nil
+		}
+		rv, err := pm.m.MockTag(ctx, int32Type, tag)
+		if err != nil {
+			return protoreflect.Value{}, err
+		}
+		return pm.enum(ctx, fd, protoreflect.EnumNumber(rv.Int()), tag)
+	case protoreflect.StringKind:
+		rv, err := pm.m.MockTag(ctx, stringType, orDefault(tag, defaultStringTag))
+		if err != nil {
+			return protoreflect.Value{}, err
+		}
+		return protoreflect.ValueOfString(rv.String()), nil
+	case protoreflect.BytesKind:
+		rv, err := pm.m.MockTag(ctx, stringType, orDefault(tag, defaultStringTag))
+		if err != nil {
+			return protoreflect.Value{}, err
+		}
+		return protoreflect.ValueOfBytes([]byte(rv.String())), nil
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		rv, err := pm.m.MockTag(ctx, int32Type, orDefault(tag, defaultIntegerTag))
+		if err != nil {
+			return protoreflect.Value{}, err
+		}
+		return protoreflect.ValueOfInt32(int32(rv.Int())), nil
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		rv, err := pm.m.MockTag(ctx, int64Type, orDefault(tag, defaultIntegerTag))
+		if err != nil {
+			return protoreflect.Value{}, err
+		}
+		return protoreflect.ValueOfInt64(rv.Int()), nil
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		rv, err := pm.m.MockTag(ctx, uint32Type, orDefault(tag, defaultIntegerTag))
+		if err != nil {
+			return protoreflect.Value{}, err
+		}
+		return protoreflect.ValueOfUint32(uint32(rv.Uint())), nil
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		rv, err := pm.m.MockTag(ctx, uint64Type, orDefault(tag, defaultIntegerTag))
+		if err != nil {
+			return protoreflect.Value{}, err
+		}
+		return protoreflect.ValueOfUint64(rv.Uint()), nil
+	case protoreflect.FloatKind:
+		rv, err := pm.m.MockTag(ctx, float32Type, orDefault(tag, defaultDecimalTag))
+		if err != nil {
+			return protoreflect.Value{}, err
+		}
+		return protoreflect.ValueOfFloat32(float32(rv.Float())), nil
+	case protoreflect.DoubleKind:
+		rv, err := pm.m.MockTag(ctx, float64Type, orDefault(tag, defaultDecimalTag))
+		if err != nil {
+			return protoreflect.Value{}, err
+		}
+		return protoreflect.ValueOfFloat64(rv.Float()), nil
+	}
+	return protoreflect.Value{}, fmt.Errorf("not support the kind:%s", fd.Kind())
+}
+
+// length mock the length of the repeated or map field, default [1,3]
+func (pm *Mock) length(ctx context.Context, tag string) (int, error) {
+	if tag == "" {
+		return 1 + gomock.GetRand(ctx).Intn(3), nil
+	}
+	rv, err := pm.m.MockTag(ctx, lengthType, tag)
+	if err != nil {
+		return 0, err
+	}
+	return rv.Len(), nil
+}
+
+// fieldTag find the mock tag by the field option, the go key and the proto key in order
+func (pm *Mock) fieldTag(goType reflect.Type, fd protoreflect.FieldDescriptor) string {
+	if pm.tagExt != nil && fd.Options() != nil && proto.HasExtension(fd.Options(), pm.tagExt) {
+		if tag, ok := proto.GetExtension(fd.Options(), pm.tagExt).(string); ok {
+			return tag
+		}
+	}
+	if goType != nil {
+		name := "." + goCamelCase(string(fd.Name()))
+		if tag, ok := pm.m.TagConfig(goType.PkgPath() + "." + goType.Name() + name); ok {
+			return tag
+		}
+		if tag, ok := pm.m.TagConfig(goType.String() + name); ok {
+			return tag
+		}
+	}
+	tag, _ := pm.m.TagConfig(string(fd.FullName()))
+	return tag
+}
+
+// goStruct return the generated struct of the message, nil for the dynamic message
+func goStruct(msg protoreflect.Message) reflect.Type {
+	rt := reflect.TypeOf(msg.Interface())
+	if rt == dynamicType || rt.Kind() != reflect.Pointer || rt.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	return rt.Elem()
+}
+
+// goCamelCase is the go name of the generated field, eg: user_id to UserId, the same as protoc-gen-go
+func goCamelCase(name string) string {
+	isLower := func(c byte) bool { return c >= 'a' && c <= 'z' }
+	b := make([]byte, 0, len(name))
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		switch {
+		case c == '_' && i == 0:
+			b = append(b, 'X')
+		case c == '_' && i+1 < len(name) && isLower(name[i+1]):
+		case c >= '0' && c <= '9':
+			b = append(b, c)
+		default:
+			if isLower(c) {
+				c -= 'a' - 'A'
+			}
+			b = append(b, c)
+			for ; i+1 < len(name) && isLower(name[i+1]); i++ {
+				b = append(b, name[i+1])
+			}
+		}
+	}
+	return string(b)
+}
+
+// wrapped return the value field of the wrapper types, eg: google.protobuf.StringValue
+func wrapped(md protoreflect.MessageDescriptor) protoreflect.FieldDescriptor {
+	name := string(md.FullName())
+	if !strings.HasPrefix(name, wrapperPrefix) || !strings.HasSuffix(name, wrapperSuffix) || md.Fields().Len() != 1 {
+		return nil
+	}
+	return md.Fields().ByName(wrapperField)
+}
+
+func setTime(msg protoreflect.Message, t time.Time) error {
+	return setSecondsNanos(msg, t.Unix(), int32(t.Nanosecond()))
+}
+
+func setDuration(msg protoreflect.Message, d time.Duration) error {
+	return setSecondsNanos(msg, int64(d/time.Second), int32(d%time.Second))
+}
+
+func setSecondsNanos(msg protoreflect.Message, seconds int64, nanos int32) error {
+	fields := msg.Descriptor().Fields()
+	sfd, nfd := fields.ByName("seconds"), fields.ByName("nanos")
+	if sfd == nil || nfd == nil {
+		return fmt.Errorf("invalid message:%s", msg.Descriptor().FullName())
+	}
+	msg.Set(sfd, protoreflect.ValueOfInt64(seconds))
+	msg.Set(nfd, protoreflect.ValueOfInt32(nanos))
+	return nil
+}
+
+// splitInto split the tag of the repeated or map field, the tag after into is for the elements or the map values.
+// the tag is separated by the default separators
+func splitInto(tag string) (string, string) {
+	tag = "," + tag
+	i := strings.Index(tag, ","+gomock.MockInto+"=")
+	if i < 0 {
+		return tag[1:], ""
+	}
+	list, rest := "", tag[i+len(gomock.MockInto)+2:]
+	if i > 0 {
+		list = tag[1:i]
+	}
+	if j := strings.Index(rest, ","); j >= 0 {
+		return list, rest[j+1:]
+	}
+	return list, ""
+}
+
+func hasTag(tag, key string) bool {
+	tag, _ = splitInto(tag)
+	return strings.HasPrefix(tag, key+"=") || strings.Contains(tag, ","+key+"=")
+}
+
+func orDefault(tag, defaultTag string) string {
+	if tag == "" {
+		return defaultTag
+	}
+	return tag
+}
@@ -0,0 +1,264 @@
+package protomock
+
+import (
+	"context"
+	"math/rand"
+	"net/mail"
+	"strings"
+	"testing"
+
+	"github.com/pigfu/gomock"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	_ "google.golang.org/protobuf/types/known/timestamppb"
+	_ "google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// userFile is the descriptor of
+//
+//	syntax = "proto3";
+//	package example;
+//	enum Status { UNKNOWN = 0; ACTIVE = 1; BANNED = 2; }
+//	message Address { string city = 1; }
+//	message User {
+//	  int64 id = 1;
+//	  string email = 2;
+//	  Status status = 3;
+//	  repeated string tags = 4;
+//	  map<string, int32> scores = 5;
+//	  google.protobuf.Timestamp created_at = 6;
+//	  google.protobuf.StringValue nickname = 7;
+//	  oneof contact { string phone = 8; Address address = 9; }
+//	  User friend = 10;
+//	  string secret = 11;
+//	}
+func userFile(t *testing.T) protoreflect.FileDescriptor {
+	t.Helper()
+	var (
+		optional = descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()
+		repeated = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+		zero     = int32(0)
+	)
+	field := func(name string, number int32, typ descriptorpb.FieldDescriptorProto_Type, typeName string) *descriptorpb.FieldDescriptorProto {
+		fd := &descriptorpb.FieldDescriptorProto{
+			Name:     proto.String(name),
+			JsonName: proto.String(name),
+			Number:   proto.Int32(number),
+			Label:    optional,
+			Type:     typ.Enum(),
+		}
+		if typeName != "" {
+			fd.TypeName = proto.String(typeName)
+		}
+		return fd
+	}
+	var (
+		tags   = field("tags", 4, descriptorpb.FieldDescriptorProto_TYPE_STRING, "")
+		scores = field("scores", 5, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".example.User.ScoresEntry")
+		phone  = field("phone", 8, descriptorpb.FieldDescriptorProto_TYPE_STRING, "")
+		addr   = field("address", 9, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".example.Address")
+	)
+	tags.Label, scores.Label = repeated, repeated
+	phone.OneofIndex, addr.OneofIndex = &zero, &zero
+	fdp := &descriptorpb.FileDescriptorProto{
+		Name:       proto.String("example/user.proto"),
+		Package:    proto.String("example"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"google/protobuf/timestamp.proto", "google/protobuf/wrappers.proto"},
+		EnumType: []*descriptorpb.EnumDescriptorProto{{
+			Name: proto.String("Status"),
+			Value: []*descriptorpb.EnumValueDescriptorProto{
+				{Name: proto.String("UNKNOWN"), Number: proto.Int32(0)},
+				{Name: proto.String("ACTIVE"), Number: proto.Int32(1)},
+				{Name: proto.String("BANNED"), Number: proto.Int32(2)},
+			},
+		}},
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name:  proto.String("Address"),
+				Field: []*descriptorpb.FieldDescriptorProto{field("city", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, "")},
+			},
+			{
+				Name: proto.String("User"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("id", 1, descriptorpb.FieldDescriptorProto_TYPE_INT64, ""),
+					field("email", 2, descriptorpb.FieldDescriptorProto_TYPE_STRING, ""),
+					field("status", 3, descriptorpb.FieldDescriptorProto_TYPE_ENUM, ".example.Status"),
+					tags, scores,
+					field("created_at", 6, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".google.protobuf.Timestamp"),
+					field("nickname", 7, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".google.protobuf.StringValue"),
+					phone, addr,
+					field("friend", 10, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".example.User"),
+					field("secret", 11, descriptorpb.FieldDescriptorProto_TYPE_STRING, ""),
+				},
+				NestedType: []*descriptorpb.DescriptorProto{{
+					Name: proto.String("ScoresEntry"),
+					Field: []*descriptorpb.FieldDescriptorProto{
+						field("key", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, ""),
+						field("value", 2, descriptorpb.FieldDescriptorProto_TYPE_INT32, ""),
+					},
+					Options: &descriptorpb.MessageOptions{MapEntry: proto.Bool(true)},
+				}},
+				OneofDecl: []*descriptorpb.OneofDescriptorProto{{Name: proto.String("contact")}},
+			},
+		},
+	}
+	fd, err := protodesc.NewFile(fdp, protoregistry.GlobalFiles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fd
+}
+
+func TestMessage(t *testing.T) {
+	md := userFile(t).Messages().ByName("User")
+	m := gomock.New()
+	m.RegisterTagConfig(map[string]string{
+		"example.User.id":       "key=integer,gte=1,lte=100",
+		"example.User.email":    "key=email",
+		"example.User.status":   "key=integer,options=1 2",
+		"example.User.tags":     "eq=2,into=1,key=string,options=vip new",
+		"example.User.scores":   "eq=3,into=1,key=integer,gte=60,lte=100",
+		"example.User.nickname": "key=string,eq=pig",
+		"example.User.secret":   "skip=1",
+	}, gomock.TagConfigOverride)
+	pm := New(m, WithMaxDepth(1))
+	for i := 0; i < 20; i++ {
+		msg := dynamicpb.NewMessage(md)
+		ctx := gomock.WithRand(context.Background(), rand.New(rand.NewSource(int64(i))))
+		if err := pm.Message(ctx, msg); err != nil {
+			t.Fatal(err)
+		}
+		get := func(name string) protoreflect.Value {
+			return msg.Get(md.Fields().ByName(protoreflect.Name(name)))
+		}
+		if id := get("id").Int(); id < 1 || id > 100 {
+			t.Errorf("unexpected id: %d", id)
+		}
+		if _, err := mail.ParseAddress(get("email").String()); err != nil {
+			t.Errorf("unexpected email: %s", get("email").String())
+		}
+		if status := get("status").Enum(); status != 1 && status != 2 {
+			t.Errorf("unexpected status: %d", status)
+		}
+		if get("tags").List().Len() != 2 || get("scores").Map().Len() < 1 {
+			t.Errorf("unexpected tags or scores: %v", msg)
+		}
+		get("scores").Map().Range(func(_ protoreflect.MapKey, v protoreflect.Value) bool {
+			if v.Int() < 60 || v.Int() > 100 {
+				t.Errorf("unexpected score: %d", v.Int())
+			}
+			return true
+		})
+		createdAt := get("created_at").Message()
+		if createdAt.Get(createdAt.Descriptor().Fields().ByName("seconds")).Int() <= 0 {
+			t.Errorf("unexpected created_at: %v", createdAt)
+		}
+		nickname := get("nickname").Message()
+		if nickname.Get(nickname.Descriptor().Fields().ByName("value")).String() != "pig" {
+			t.Errorf("unexpected nickname: %v", nickname)
+		}
+		if msg.WhichOneof(md.Oneofs().ByName("contact")) == nil {
+			t.Error("expect one of the contact")
+		}
+		if msg.Has(md.Fields().ByName("secret")) {
+			t.Error("expect the secret skipped")
+		}
+		if !msg.Has(md.Fields().ByName("friend")) {
+			t.Error("expect the friend")
+		}
+		if friend := get("friend").Message(); friend.Has(md.Fields().ByName("friend")) {
+			t.Error("expect the depth limit")
+		}
+	}
+
+	msg1, msg2 := dynamicpb.NewMessage(md), dynamicpb.NewMessage(md)
+	_ = pm.Message(gomock.WithRand(context.Background(), rand.New(rand.NewSource(1))), msg1)
+	_ = pm.Message(gomock.WithRand(context.Background(), rand.New(rand.NewSource(1))), msg2)
+	msg1.Clear(md.Fields().ByName("created_at")) //the timestamp is relative to now
+	msg2.Clear(md.Fields().ByName("created_at"))
+	if !proto.Equal(msg1, msg2) {
+		t.Errorf("expect the same message by the same seed:\n%v\n%v", msg1, msg2)
+	}
+	//the range takes the declared numbers only
+	m.RegisterTagConfig(map[string]string{"example.User.status": "key=integer,gte=1,lte=100"}, gomock.TagConfigOverride)
+	for i := 0; i < 20; i++ {
+		msg := dynamicpb.NewMessage(md)
+		if err := pm.Message(gomock.WithRand(context.Background(), rand.New(rand.NewSource(int64(i)))), msg); err != nil {
+			t.Fatal(err)
+		}
+		if status := msg.Get(md.Fields().ByName("status")).Enum(); status != 1 && status != 2 {
+			t.Errorf("unexpected status: %d", status)
+		}
+	}
+	m.RegisterTagConfig(map[string]string{"example.User.status": "key=integer,gte=3,lte=100"}, gomock.TagConfigOverride)
+	if err := pm.Message(context.Background(), dynamicpb.NewMessage(md)); err == nil || !strings.Contains(err.Error(), "no declared enum number") {
+		t.Errorf("expect no declared enum number error, got %v", err)
+	}
+	data, _ := protojson.Marshal(msg1)
+	t.Logf("success: %s", data)
+}
+
+func TestTagExtension(t *testing.T) {
+	extFile, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:       proto.String("example/mock.proto"),
+		Package:    proto.String("example"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"google/protobuf/descriptor.proto"},
+		Extension: []*descriptorpb.FieldDescriptorProto{{
+			Name:     proto.String("mock"),
+			JsonName: proto.String("mock"),
+			Number:   proto.Int32(50001),
+			Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+			Extendee: proto.String(".google.protobuf.FieldOptions"),
+		}},
+	}, protoregistry.GlobalFiles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	xt := dynamicpb.NewExtensionType(extFile.Extensions().ByName("mock"))
+	opts := &descriptorpb.FieldOptions{}
+	proto.SetExtension(opts, xt, "key=email")
+	files := &protoregistry.Files{}
+	if err = files.RegisterFile(extFile); err != nil {
+		t.Fatal(err)
+	}
+	file, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:       proto.String("example/contact.proto"),
+		Package:    proto.String("example"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"example/mock.proto"},
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("Contact"),
+			Field: []*descriptorpb.FieldDescriptorProto{{
+				Name:     proto.String("email"),
+				JsonName: proto.String("email"),
+				Number:   proto.Int32(1),
+				Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+				Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+				Options:  opts,
+			}},
+		}},
+	}, files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	md := file.Messages().ByName("Contact")
+	msg := dynamicpb.NewMessage(md)
+	if err = New(gomock.New(), WithTagExtension(xt)).Message(context.Background(), msg); err != nil {
+		t.Fatal(err)
+	}
+	email := msg.Get(md.Fields().ByName("email")).String()
+	if _, err = mail.ParseAddress(email); err != nil {
+		t.Errorf("unexpected email: %s", email)
+	}
+	if goCamelCase("user_id") != "UserId" || goCamelCase("field_2d") != "Field_2D" {
+		t.Errorf("unexpected go name: %s %s", goCamelCase("user_id"), goCamelCase("field_2d"))
+	}
+}
@@ -0,0 +1,107 @@
+package gomock
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing/quick"
+)
+
+const quickSize = 50 //the size of the values given by QuickValues, the same as testing/quick
+
+type sizeKey struct{}
+
+// WithSize limit the length of the strings and slices to size in this call as long as the tags are kept,
+// eg: the length of gte=3,lte=10 is in [3,5] for size 5
+func WithSize(ctx context.Context, size int) context.Context {
+	if size < 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, sizeKey{}, size)
+}
+
+func getSize(ctx context.Context) (int, bool) {
+	size, ok := ctx.Value(sizeKey{}).(int)
+	return size, ok
+}
+
+// Generate mock the value of T by r and size for testing/quick, it panics if the value can not be mocked.
+// the struct implements quick.Generator by it:
+//
+//	func (User) Generate(r *rand.Rand, size int) reflect.Value {
+//		return gomock.Generate[User](m, r, size)
+//	}
+func Generate[T any](m *Mock, r *rand.Rand, size int) reflect.Value {
+	value, err := makeOne[T](WithSize(WithRand(context.Background(), r), size), m)
+	if err != nil {
+		panic(fmt.Sprintf("gomock: generate %T,err:%v", value, err))
+	}
+	return reflect.ValueOf(value)
+}
+
+var (
+	defaultQuickMock = New() //the Mock of Quick.Generate, it is never replaced
+	generatorType    = reflect.TypeOf((*quick.Generator)(nil)).Elem()
+)
+
+// Quick wrap the struct to implement quick.Generator by a default Mock, the struct of any type is usable in quick.Check:
+//
+//	quick.Check(func(q gomock.Quick[User]) bool { return q.Value.Age >= 18 }, nil)
+//
+// QuickValues mocks it by the given Mock
+type Quick[T any] struct {
+	Value T
+}
+
+func (q Quick[T]) Generate(r *rand.Rand, size int) reflect.Value {
+	return q.generate(defaultQuickMock, r, size)
+}
+
+func (Quick[T]) generate(m *Mock, r *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(Quick[T]{Value: Generate[T](m, r, size).Interface().(T)})
+}
+
+// quickGenerator is implemented by Quick to be mocked by the Mock of QuickValues
+type quickGenerator interface {
+	generate(m *Mock, r *rand.Rand, size int) reflect.Value
+}
+
+// QuickValues return the quick.Config.Values generating the arguments of f, the Quick and the struct arguments are
+// mocked by m, the others are generated by testing/quick. it panics if f is not a function or an argument can not be
+// generated, like quick.Check does.
+//
+//	err := quick.Check(f, &quick.Config{Values: gomock.QuickValues(m, f)})
+func QuickValues(m *Mock, f any) func([]reflect.Value, *rand.Rand) {
+	ft := reflect.TypeOf(f)
+	if ft == nil || ft.Kind() != reflect.Func {
+		panic("gomock: QuickValues of a non-function")
+	}
+	return func(args []reflect.Value, r *rand.Rand) {
+		for i := range args {
+			args[i] = quickValue(m, ft.In(i), r)
+		}
+	}
+}
+
+func quickValue(m *Mock, rt reflect.Type, r *rand.Rand) reflect.Value {
+	if qg, ok := reflect.Zero(rt).Interface().(quickGenerator); ok {
+		return qg.generate(m, r, quickSize)
+	}
+	st, isPtr := m.Indirect(rt)
+	if st.Kind() != reflect.Struct || rt.Implements(generatorType) {
+		value, ok := quick.Value(rt, r)
+		if !ok {
+			panic(fmt.Sprintf("gomock: generate %s", rt))
+		}
+		return value
+	}
+	ptr := reflect.New(st)
+	if err := m.StructCtx(WithSize(WithRand(context.Background(), r), quickSize), ptr.Interface()); err != nil {
+		panic(fmt.Sprintf("gomock: generate %s,err:%v", rt, err))
+	}
+	if isPtr {
+		return ptr
+	}
+	return ptr.Elem()
+}
@@ -0,0 +1,28 @@
+package gomock
+
+import (
+	"context"
+	"math/rand"
+
+	"github.com/pigfu/gomock/regen"
+)
+
+type randKey struct{}
+
+// WithRand mock by the random source r in this call, the same seeded source gives the same values
+// except the time mock function. r is not safe for concurrent use, do not share it between goroutines.
+func WithRand(ctx context.Context, r *rand.Rand) context.Context {
+	if r == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, randKey{}, r)
+}
+
+// GetRand return the random source of the call, the custom mock function should use it
+// to keep the seeded output deterministic
+func GetRand(ctx context.Context) *rand.Rand {
+	if r, ok := ctx.Value(randKey{}).(*rand.Rand); ok {
+		return r
+	}
+	return regen.GlobalRand()
+}
@@ -7,7 +7,7 @@ import (
 	"strings"
 )
 
-type generator func(*syntax.Regexp, *strings.Builder) error
+type generator func(*rand.Rand, *syntax.Regexp, *strings.Builder) error
 
 var (
 	generatorMap map[syntax.Op]generator
@@ -48,90 +48,109 @@ const (
 	asciiMaxPrintableChar = 126
 )
 
+// globalSource use the top-level functions of math/rand, it is safe for concurrent use
+type globalSource struct{}
+
+func (globalSource) Int63() int64   { return rand.Int63() }
+func (globalSource) Uint64() uint64 { return rand.Uint64() }
+func (globalSource) Seed(_ int64)   {}
+
+var globalRand = rand.New(globalSource{})
+
+// GlobalRand return the random source using the top-level functions of math/rand, it is safe for concurrent use
+func GlobalRand() *rand.Rand {
+	return globalRand
+}
+
 func Generate(pattern string) (string, error) {
+	return GenerateRand(pattern, globalRand)
+}
+
+// GenerateRand generate the string by the random source r, the same source gives the same string
+func GenerateRand(pattern string, r *rand.Rand) (string, error) {
 	reg, err := syntax.Parse(pattern, syntax.Perl)
 	if err != nil {
 		return "", err
 	}
 	output := &strings.Builder{}
-	err = generate(reg, output)
+	err = generate(r, reg, output)
 	return output.String(), err
 }
 
-func generate(regexp *syntax.Regexp, b *strings.Builder) error {
+func generate(r *rand.Rand, regexp *syntax.Regexp, b *strings.Builder) error {
 	if f, ok := generatorMap[regexp.Op]; ok {
-		return f(regexp, b)
+		return f(r, regexp, b)
 	}
 	return fmt.Errorf("not support the type %s", regexp.Op)
 }
 
-func regEmpty(_ *syntax.Regexp, _ *strings.Builder) error {
+func regEmpty(_ *rand.Rand, _ *syntax.Regexp, _ *strings.Builder) error {
 	return nil
 }
 
-func regLiteral(regexp *syntax.Regexp, b *strings.Builder) error {
+func regLiteral(_ *rand.Rand, regexp *syntax.Regexp, b *strings.Builder) error {
 	b.WriteString(string(regexp.Rune))
 	return nil
 }
 
-func regWordBoundary(_ *syntax.Regexp, b *strings.Builder) error {
-	b.WriteRune(noneWord[rand.Intn(len(noneWord))])
+func regWordBoundary(r *rand.Rand, _ *syntax.Regexp, b *strings.Builder) error {
+	b.WriteRune(noneWord[r.Intn(len(noneWord))])
 	return nil
 }
 
-func regNoWordBoundary(_ *syntax.Regexp, b *strings.Builder) error {
-	b.WriteRune(word[rand.Intn(len(word))])
+func regNoWordBoundary(r *rand.Rand, _ *syntax.Regexp, b *strings.Builder) error {
+	b.WriteRune(word[r.Intn(len(word))])
 	return nil
 }
 
 // *  0 or more of previous expression
-func regStar(regexp *syntax.Regexp, b *strings.Builder) (err error) {
-	return genRepeat(regexp.Sub[0], b, rand.Intn(defaultMaxTimes))
+func regStar(r *rand.Rand, regexp *syntax.Regexp, b *strings.Builder) (err error) {
+	return genRepeat(r, regexp.Sub[0], b, r.Intn(defaultMaxTimes))
 }
 
 // ? 0 or 1 of previous expression
-func regQuest(regexp *syntax.Regexp, b *strings.Builder) (err error) {
-	return genRepeat(regexp.Sub[0], b, rand.Intn(2))
+func regQuest(r *rand.Rand, regexp *syntax.Regexp, b *strings.Builder) (err error) {
+	return genRepeat(r, regexp.Sub[0], b, r.Intn(2))
 }
 
 // + 1 or more of previous expression
-func regPlus(regexp *syntax.Regexp, b *strings.Builder) (err error) {
-	return genRepeat(regexp.Sub[0], b, 1+rand.Intn(defaultMaxTimes))
+func regPlus(r *rand.Rand, regexp *syntax.Regexp, b *strings.Builder) (err error) {
+	return genRepeat(r, regexp.Sub[0], b, 1+r.Intn(defaultMaxTimes))
 }
 
 // {m,n} number of previous expression
-func regRepeat(regexp *syntax.Regexp, b *strings.Builder) (err error) {
+func regRepeat(r *rand.Rand, regexp *syntax.Regexp, b *strings.Builder) (err error) {
 	max := regexp.Max
 	if max == -1 {
 		max = regexp.Min + defaultMaxTimes
 	}
-	return genRepeat(regexp.Sub[0], b, regexp.Min+rand.Intn(max-regexp.Min+1))
+	return genRepeat(r, regexp.Sub[0], b, regexp.Min+r.Intn(max-regexp.Min+1))
 }
 
-func genRepeat(regexp *syntax.Regexp, b *strings.Builder, number int) (err error) {
+func genRepeat(r *rand.Rand, regexp *syntax.Regexp, b *strings.Builder, number int) (err error) {
 	for i := 0; i < number; i++ {
-		if err = generate(regexp, b); err != nil {
+		if err = generate(r, regexp, b); err != nil {
 			return err
 		}
 	}
 	return
 }
 
-func regCharClass(regexp *syntax.Regexp, b *strings.Builder) error {
+func regCharClass(r *rand.Rand, regexp *syntax.Regexp, b *strings.Builder) error {
 	if len(regexp.Rune)&1 != 0 || len(regexp.Rune) == 0 {
 		return nil
 	}
 	//[0,1,2,3,4,5]
-	randIndex := rand.Intn(len(regexp.Rune) / 2)
-	l, r := regexp.Rune[randIndex*2], regexp.Rune[randIndex*2+1]
-	b.WriteRune(rune(rand.Int63n(int64(r-l)+1) + int64(l))) //[m,n]
+	randIndex := r.Intn(len(regexp.Rune) / 2)
+	left, right := regexp.Rune[randIndex*2], regexp.Rune[randIndex*2+1]
+	b.WriteRune(rune(r.Int63n(int64(right-left)+1) + int64(left))) //[m,n]
 	return nil
 }
 
-func regConcat(regexp *syntax.Regexp, b *strings.Builder) error {
+func regConcat(r *rand.Rand, regexp *syntax.Regexp, b *strings.Builder) error {
 	var err error
 	for _, sub := range regexp.Sub {
-		if err = generate(sub, b); err != nil {
+		if err = generate(r, sub, b); err != nil {
 			return err
 		}
 	}
@@ -139,29 +158,29 @@ func regConcat(regexp *syntax.Regexp, b *strings.Builder) error {
 }
 
 // Alternation
-func regAlternate(regexp *syntax.Regexp, b *strings.Builder) error {
+func regAlternate(r *rand.Rand, regexp *syntax.Regexp, b *strings.Builder) error {
 	if len(regexp.Sub) == 0 {
 		return nil
 	}
-	return generate(regexp.Sub[rand.Intn(len(regexp.Sub))], b)
+	return generate(r, regexp.Sub[r.Intn(len(regexp.Sub))], b)
 }
 
 // Any character (except \n newline)
-func regAnyCharNotNL(_ *syntax.Regexp, b *strings.Builder) error {
-	genPrintableChar(b)
+func regAnyCharNotNL(r *rand.Rand, _ *syntax.Regexp, b *strings.Builder) error {
+	genPrintableChar(r, b)
 	return nil
 }
 
 // Any character
-func regAnyChar(_ *syntax.Regexp, b *strings.Builder) error {
-	genPrintableChar(b)
+func regAnyChar(r *rand.Rand, _ *syntax.Regexp, b *strings.Builder) error {
+	genPrintableChar(r, b)
 	return nil
 }
 
-func genPrintableChar(b *strings.Builder) {
-	b.WriteRune(rune(rand.Intn(asciiMaxPrintableChar-asciiMinPrintableChar+1) + asciiMinPrintableChar))
+func genPrintableChar(r *rand.Rand, b *strings.Builder) {
+	b.WriteRune(rune(r.Intn(asciiMaxPrintableChar-asciiMinPrintableChar+1) + asciiMinPrintableChar))
 }
 
-func regCapture(regexp *syntax.Regexp, b *strings.Builder) error {
-	return generate(regexp.Sub[0], b)
+func regCapture(r *rand.Rand, regexp *syntax.Regexp, b *strings.Builder) error {
+	return generate(r, regexp.Sub[0], b)
 }
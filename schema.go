@@ -0,0 +1,343 @@
+package gomock
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	schemaRefPrefix = "#/components/schemas/"
+	schemaDialect   = "https://json-schema.org/draft/2020-12/schema"
+	schemaNull      = "null"
+
+	// OpenAPIVersion is the version of the document given by OpenAPI, the numeric exclusiveMinimum/exclusiveMaximum
+	// and the null type are OpenAPI 3.1
+	OpenAPIVersion = "3.1.0"
+)
+
+var componentNameReplacer = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// Schema is the JSON Schema (draft 2020-12) and OpenAPI 3.1 schema derived from the mock tags
+type Schema struct {
+	Dialect          string             `json:"$schema,omitempty" yaml:"$schema,omitempty"`
+	Ref              string             `json:"$ref,omitempty" yaml:"$ref,omitempty"`
+	Type             string             `json:"type,omitempty" yaml:"type,omitempty"`
+	Format           string             `json:"format,omitempty" yaml:"format,omitempty"`
+	Properties       map[string]*Schema `json:"properties,omitempty" yaml:"properties,omitempty"`
+	Items            *Schema            `json:"items,omitempty" yaml:"items,omitempty"`
+	Const            any                `json:"const,omitempty" yaml:"const,omitempty"`
+	Enum             []any              `json:"enum,omitempty" yaml:"enum,omitempty"`
+	Pattern          string             `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+	Minimum          *float64           `json:"minimum,omitempty" yaml:"minimum,omitempty"`
+	Maximum          *float64           `json:"maximum,omitempty" yaml:"maximum,omitempty"`
+	ExclusiveMinimum *float64           `json:"exclusiveMinimum,omitempty" yaml:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum *float64           `json:"exclusiveMaximum,omitempty" yaml:"exclusiveMaximum,omitempty"`
+	MinLength        *int               `json:"minLength,omitempty" yaml:"minLength,omitempty"`
+	MaxLength        *int               `json:"maxLength,omitempty" yaml:"maxLength,omitempty"`
+	MinItems         *int               `json:"minItems,omitempty" yaml:"minItems,omitempty"`
+	MaxItems         *int               `json:"maxItems,omitempty" yaml:"maxItems,omitempty"`
+	AllOf            []*Schema          `json:"allOf,omitempty" yaml:"allOf,omitempty"`
+	OneOf            []*Schema          `json:"oneOf,omitempty" yaml:"oneOf,omitempty"`
+	AnyOf            []*Schema          `json:"anyOf,omitempty" yaml:"anyOf,omitempty"`
+}
+
+// OpenAPIDoc is the OpenAPI document of the component schemas
+type OpenAPIDoc struct {
+	OpenAPI    string      `json:"openapi" yaml:"openapi"`
+	Info       OpenAPIInfo `json:"info" yaml:"info"`
+	Components struct {
+		Schemas map[string]*Schema `json:"schemas" yaml:"schemas"`
+	} `json:"components" yaml:"components"`
+}
+
+type OpenAPIInfo struct {
+	Title   string `json:"title" yaml:"title"`
+	Version string `json:"version" yaml:"version"`
+}
+
+// Schema return the JSON Schema of the struct, only the fields with mock tags are included, named by the json tag.
+// eq, options, reg and the range become const, enum, pattern, minimum/maximum, minLength/maxLength or minItems/maxItems,
+// email and time become the format, the ptr fields are nullable by anyOf with the null type
+func (m *Mock) Schema(s any) (*Schema, error) {
+	fl, err := m.schemaField(s)
+	if err != nil {
+		return nil, err
+	}
+	schema := (&schemaBuilder{m: m}).build(fl)
+	schema.Dialect = schemaDialect
+	return schema, nil
+}
+
+// Components return the OpenAPI 3.1 component schemas of the structs keyed by the type name, the nested structs are
+// components too and referred by $ref. the type of the same name in another package is keyed by the package and
+// the name, eg: "model.User", see Schema and OpenAPI
+func (m *Mock) Components(values ...any) (map[string]*Schema, error) {
+	sb := &schemaBuilder{
+		m:          m,
+		components: make(map[string]*Schema),
+		names:      make(map[reflect.Type]string),
+	}
+	for _, s := range values {
+		fl, err := m.schemaField(s)
+		if err != nil {
+			return nil, err
+		}
+		sb.component(fl)
+	}
+	return sb.components, nil
+}
+
+// OpenAPI return the OpenAPI 3.1 document of the component schemas of the structs, see Components
+func (m *Mock) OpenAPI(info OpenAPIInfo, values ...any) (*OpenAPIDoc, error) {
+	components, err := m.Components(values...)
+	if err != nil {
+		return nil, err
+	}
+	doc := &OpenAPIDoc{OpenAPI: OpenAPIVersion, Info: info}
+	doc.Components.Schemas = components
+	return doc, nil
+}
+
+func (m *Mock) schemaField(s any) (FieldLevel, error) {
+	rt := reflect.TypeOf(s)
+	if rt != nil {
+		rt, _ = m.Indirect(rt)
+	}
+	if rt == nil || rt.Kind() != reflect.Struct {
+		return nil, errors.New("not a struct or struct ptr")
+	}
+	return m.genCache(context.Background(), reflect.New(rt))
+}
+
+// schemaBuilder build the schema inline if components is nil
+type schemaBuilder struct {
+	m          *Mock
+	components map[string]*Schema
+	names      map[reflect.Type]string //the component names of the types
+}
+
+// component add the struct to the components and return the reference
+func (sb *schemaBuilder) component(fl FieldLevel) *Schema {
+	if fl.GetType().Name() == "" { //anonymous struct
+		return sb.object(fl)
+	}
+	name, ok := sb.names[fl.GetType()]
+	if !ok {
+		name = sb.componentName(fl.GetType())
+		sb.names[fl.GetType()] = name
+		sb.components[name] = nil //the recursive type refers itself
+		sb.components[name] = sb.object(fl)
+	}
+	return &Schema{Ref: schemaRefPrefix + name}
+}
+
+// componentName return the type name, or the package and the name if it is taken by another type
+func (sb *schemaBuilder) componentName(rt reflect.Type) string {
+	candidates := []string{rt.Name(), rt.String(), rt.PkgPath() + "." + rt.Name()}
+	for _, name := range candidates {
+		if name = componentNameReplacer.ReplaceAllString(name, "_"); !sb.taken(name) {
+			return name
+		}
+	}
+	name := componentNameReplacer.ReplaceAllString(candidates[2], "_")
+	for i := 2; ; i++ {
+		if !sb.taken(name + "_" + strconv.Itoa(i)) {
+			return name + "_" + strconv.Itoa(i)
+		}
+	}
+}
+
+func (sb *schemaBuilder) taken(name string) bool {
+	_, ok := sb.components[name]
+	return ok
+}
+
+// nullable allow null for the ptr, the schema is wrapped by anyOf with the null type
+func nullable(schema *Schema) *Schema {
+	return &Schema{AnyOf: []*Schema{schema, {Type: schemaNull}}}
+}
+
+func (sb *schemaBuilder) build(fl FieldLevel) *Schema {
+	switch fl.GetKind() {
+	case reflect.Struct:
+		if sb.components != nil {
+			return sb.component(fl)
+		}
+		return sb.object(fl)
+	case reflect.Slice:
+		return sb.array(fl)
+	}
+	schema := sb.kindSchema(fl.GetType())
+	tm := fl.GetTags()
+	switch {
+	case fl.GetKind() == reflect.String:
+		stringSchema(schema, tm)
+	case isIntegerKind(fl.GetType()) || isDecimalKind(fl.GetType()):
+		numberSchema(schema, tm, fl.GetType())
+	}
+	return schema
+}
+
+func (sb *schemaBuilder) object(fl FieldLevel) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	for _, child := range fl.GetChildren() {
+		rs := fl.GetType().Field(child.GetIndex())
+		name := strings.Split(rs.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = rs.Name
+		}
+		schema.Properties[name] = sb.child(child)
+	}
+	return schema
+}
+
+func (sb *schemaBuilder) array(fl FieldLevel) *Schema {
+	schema, tm := &Schema{Type: "array"}, fl.GetTags()
+	if len(fl.GetChildren()) > 0 {
+		schema.Items = sb.child(fl.GetChildren()[0])
+	} else {
+		rt, isPtr := sb.m.Indirect(fl.GetType().Elem())
+		if schema.Items = sb.kindSchema(rt); isPtr {
+			schema.Items = nullable(schema.Items)
+		}
+	}
+	if eq := tm.Key(MockEqual).GetInt(); eq > 0 {
+		schema.MinItems, schema.MaxItems = &eq, &eq
+		return schema
+	}
+	schema.MinItems, schema.MaxItems = lengthRange(tm)
+	return schema
+}
+
+// child build the schema of the field or the element, the ptr is nullable
+func (sb *schemaBuilder) child(fl FieldLevel) *Schema {
+	if schema := sb.build(fl); !fl.IsPtr() {
+		return schema
+	} else {
+		return nullable(schema)
+	}
+}
+
+// kindSchema return the schema of the type without the tags
+func (sb *schemaBuilder) kindSchema(rt reflect.Type) *Schema {
+	switch rt.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.Slice:
+		elem, isPtr := sb.m.Indirect(rt.Elem())
+		schema := &Schema{Type: "array", Items: sb.kindSchema(elem)}
+		if isPtr {
+			schema.Items = nullable(schema.Items)
+		}
+		return schema
+	case reflect.Struct, reflect.Map:
+		return &Schema{Type: "object"}
+	}
+	return &Schema{}
+}
+
+func stringSchema(schema *Schema, tm TagLevelMap) {
+	switch tm.Key(MockKey).GetKey() {
+	case makeEmail:
+		schema.Format = "email"
+		return
+	case makeTime:
+		switch tm.Key(MockTime).GetStr() {
+		case time.RFC3339, time.RFC3339Nano:
+			schema.Format = "date-time"
+		case time.DateOnly:
+			schema.Format = "date"
+		}
+		return
+	case makeString:
+	default:
+		return
+	}
+	switch {
+	case tm.Key(MockEqual).GetStr() != "":
+		schema.Const = tm.Key(MockEqual).GetStr()
+	case tm.Key(MockOptions).Exists():
+		for _, option := range tm.Key(MockOptions).GetStrSet() {
+			schema.Enum = append(schema.Enum, option)
+		}
+	case tm.Key(MockRegExp).Exists():
+		schema.Pattern = "^(?:" + tm.Key(MockRegExp).GetStr() + ")$"
+	default:
+		schema.MinLength, schema.MaxLength = lengthRange(tm)
+	}
+}
+
+func numberSchema(schema *Schema, tm TagLevelMap, rt reflect.Type) {
+	switch key := tm.Key(MockKey).GetKey(); {
+	case key == makeTime && rt.Kind() == reflect.Int64:
+		schema.Format = "int64" //the timestamp
+		return
+	case key != makeInteger && key != makeDecimal:
+		return
+	}
+	number := func(tag TagLevel) float64 {
+		if isDecimalKind(rt) {
+			return tag.GetFloat64()
+		}
+		return float64(tag.GetInt64())
+	}
+	switch {
+	case tm.Key(MockEqual).Exists():
+		schema.Const = number(tm.Key(MockEqual))
+	case tm.Key(MockOptions).Exists():
+		if isDecimalKind(rt) {
+			for _, option := range tm.Key(MockOptions).GetFloat64Set() {
+				schema.Enum = append(schema.Enum, option)
+			}
+		} else {
+			for _, option := range tm.Key(MockOptions).GetInt64Set() {
+				schema.Enum = append(schema.Enum, option)
+			}
+		}
+	case tm.Key(MockRegExp).Exists(): //the pattern is not for number
+	default:
+		bound := func(key string) *float64 {
+			if !tm.Key(key).Exists() {
+				return nil
+			}
+			value := number(tm.Key(key))
+			return &value
+		}
+		schema.ExclusiveMinimum, schema.Minimum = bound(MockGt), bound(MockGte)
+		schema.ExclusiveMaximum, schema.Maximum = bound(MockLt), bound(MockLte)
+	}
+}
+
+// lengthRange return the min and max length of the range tags, nil if not limited
+func lengthRange(tm TagLevelMap) (min, max *int) {
+	gte, gteExists := makeGteVal(reflect.Uint8, tm.Key(MockGt).GetInt(), tm.Key(MockGte).GetInt(),
+		tm.Key(MockGt).Exists(), tm.Key(MockGte).Exists())
+	lt, ltExists := makeLtVal(reflect.Uint8, tm.Key(MockLt).GetInt(), tm.Key(MockLte).GetInt(),
+		tm.Key(MockLt).Exists(), tm.Key(MockLte).Exists())
+	if gteExists && gte > 0 {
+		min = &gte
+	}
+	if ltExists {
+		lt--
+		max = &lt
+	}
+	return
+}
@@ -0,0 +1,408 @@
+package gomock
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+type SQLDialect string
+
+const (
+	Postgres SQLDialect = "postgres"
+	MySQL    SQLDialect = "mysql"
+	SQLite   SQLDialect = "sqlite"
+
+	defaultSQLBatch = 100
+)
+
+// the max parameters of a statement
+var sqlMaxArgs = map[SQLDialect]int{
+	Postgres: 65535,
+	MySQL:    65535,
+	SQLite:   32766,
+}
+
+var (
+	valuerType     = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+	timeType       = reflect.TypeOf(time.Time{})
+	mysqlEscaper   = strings.NewReplacer("\\", "\\\\", "'", "\\'", "\x00", "\\0", "\n", "\\n", "\r", "\\r", "\x1a", "\\Z")
+	quoteEscaper   = strings.NewReplacer("'", "''")
+	identEscaper   = strings.NewReplacer(`"`, `""`)
+	backtickEscape = strings.NewReplacer("`", "``")
+)
+
+type SQLConfig struct {
+	Dialect SQLDialect
+	Table   string //default the TableName method of T or the plural snake case of the type name, eg: user_infos
+	Batch   int    //the rows of a statement, default 100, limited by the max parameters of the dialect
+}
+
+// SQLExecer is satisfied by *sql.DB, *sql.Tx and *sql.Conn
+type SQLExecer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// WriteInsert mock n values of T and write them as batched INSERT statements with the literals quoted for the dialect.
+// the columns are the mocked fields of the base types, time.Time, []byte and driver.Valuer in the field order, named by
+// the `db` tag, the `gorm:"column:name"` tag or the snake case of the field name, `db:"-"` and `gorm:"-"` are skipped.
+// the embedded structs are flattened, the other structs and slices are skipped, eg: the relations
+func WriteInsert[T any](ctx context.Context, m *Mock, w io.Writer, n int, config SQLConfig) error {
+	return insertN[T](ctx, m, n, config, func(table *sqlTable, rows [][]driver.Value) error {
+		var sb strings.Builder
+		sb.WriteString(table.insert())
+		for i, row := range rows {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			sb.WriteByte('(')
+			for j, value := range row {
+				if j > 0 {
+					sb.WriteString(", ")
+				}
+				literal, err := table.literal(value)
+				if err != nil {
+					return fmt.Errorf("field:%s,err:%v", table.columns[j].name, err)
+				}
+				sb.WriteString(literal)
+			}
+			sb.WriteByte(')')
+		}
+		sb.WriteString(";\n")
+		_, err := io.WriteString(w, sb.String())
+		return err
+	})
+}
+
+// ExecInsert mock n values of T and execute the batched INSERT statements with the placeholders through db, see WriteInsert.
+// pass a *sql.Tx to seed in a transaction
+func ExecInsert[T any](ctx context.Context, m *Mock, db SQLExecer, n int, config SQLConfig) error {
+	return insertN[T](ctx, m, n, config, func(table *sqlTable, rows [][]driver.Value) error {
+		var (
+			sb   strings.Builder
+			args = make([]any, 0, len(rows)*len(table.columns))
+		)
+		sb.WriteString(table.insert())
+		for i, row := range rows {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			sb.WriteByte('(')
+			for j, value := range row {
+				if j > 0 {
+					sb.WriteString(", ")
+				}
+				args = append(args, value)
+				sb.WriteString(table.placeholder(len(args)))
+			}
+			sb.WriteByte(')')
+		}
+		_, err := db.ExecContext(ctx, sb.String(), args...)
+		return err
+	})
+}
+
+// insertN mock the values and flush them by batch
+func insertN[T any](ctx context.Context, m *Mock, n int, config SQLConfig,
+	flush func(*sqlTable, [][]driver.Value) error) error {
+	table, err := newSQLTable[T](ctx, m, config)
+	if err != nil {
+		return err
+	}
+	rows := make([][]driver.Value, 0, table.batch)
+	err = writeN[T](ctx, m, n, func(value T) error {
+		row, err := table.row(reflect.ValueOf(&value).Elem())
+		if err != nil {
+			return err
+		}
+		if rows = append(rows, row); len(rows) < table.batch {
+			return nil
+		}
+		defer func() { rows = rows[:0] }()
+		return flush(table, rows)
+	})
+	if err != nil || len(rows) == 0 {
+		return err
+	}
+	return flush(table, rows)
+}
+
+type sqlTable struct {
+	dialect SQLDialect
+	name    string
+	columns []sqlColumn
+	batch   int
+}
+
+// sqlColumn is a flattened field, path is the field indexes from the root
+type sqlColumn struct {
+	name string
+	path []int
+}
+
+func newSQLTable[T any](ctx context.Context, m *Mock, config SQLConfig) (*sqlTable, error) {
+	maxArgs, ok := sqlMaxArgs[config.Dialect]
+	if !ok {
+		return nil, fmt.Errorf("not support the dialect:%s", config.Dialect)
+	}
+	rt := reflect.TypeOf((*T)(nil)).Elem()
+	if rt, _ = m.Indirect(rt); rt.Kind() != reflect.Struct {
+		return nil, errors.New("not a struct or struct ptr")
+	}
+	fl, err := m.genCache(ctx, reflect.New(rt))
+	if err != nil {
+		return nil, err
+	}
+	table := &sqlTable{dialect: config.Dialect, name: config.Table, columns: sqlColumns(fl, "", nil), batch: config.Batch}
+	if table.name == "" {
+		table.name = tableName(rt)
+	}
+	if table.name == "" {
+		return nil, errors.New("the table name is required for the anonymous struct")
+	}
+	if len(table.columns) == 0 {
+		return nil, fmt.Errorf("no mocked column of the table:%s", table.name)
+	}
+	if table.batch <= 0 {
+		table.batch = defaultSQLBatch
+	}
+	if limit := maxArgs / len(table.columns); table.batch > limit {
+		table.batch = limit
+	}
+	return table, nil
+}
+
+// tableName return the name by the TableName method like gorm, or the plural snake case of the type name
+func tableName(rt reflect.Type) string {
+	if tabler, ok := reflect.New(rt).Interface().(interface{ TableName() string }); ok {
+		return tabler.TableName()
+	}
+	if rt.Name() == "" {
+		return ""
+	}
+	return plural(snakeCase(rt.Name()))
+}
+
+func plural(name string) string {
+	switch {
+	case strings.HasSuffix(name, "s"), strings.HasSuffix(name, "x"),
+		strings.HasSuffix(name, "ch"), strings.HasSuffix(name, "sh"):
+		return name + "es"
+	case strings.HasSuffix(name, "y") && len(name) > 1 && !strings.ContainsRune("aeiou", rune(name[len(name)-2])):
+		return name[:len(name)-1] + "ies"
+	}
+	return name + "s"
+}
+
+func sqlColumns(fl FieldLevel, prefix string, path []int) []sqlColumn {
+	var columns []sqlColumn
+	for _, child := range fl.GetChildren() {
+		rs := fl.GetType().Field(child.GetIndex())
+		name, ok := sqlColumnName(rs)
+		if !ok {
+			continue
+		}
+		childPath := appendPath(path, child.GetIndex())
+		embedPrefix, embedded := sqlEmbedded(rs)
+		if embedded && child.GetKind() == reflect.Struct && !sqlValueType(child.GetType()) {
+			columns = append(columns, sqlColumns(child, prefix+embedPrefix, childPath)...)
+			continue
+		}
+		if child.GetMockFunc() != nil && sqlValueType(child.GetType()) {
+			columns = append(columns, sqlColumn{name: prefix + name, path: childPath})
+		}
+	}
+	return columns
+}
+
+// sqlColumnName return the column name of the field, false if it is skipped
+func sqlColumnName(rs reflect.StructField) (string, bool) {
+	if name, ok := rs.Tag.Lookup("db"); ok {
+		if name, _, _ = strings.Cut(name, ","); name == "-" {
+			return "", false
+		}
+		if name != "" {
+			return name, true
+		}
+	}
+	for _, option := range strings.Split(rs.Tag.Get("gorm"), ";") {
+		key, value, _ := strings.Cut(strings.TrimSpace(option), ":")
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "-":
+			if value == "" || value == "all" {
+				return "", false
+			}
+		case "column":
+			return strings.TrimSpace(value), true
+		}
+	}
+	return snakeCase(rs.Name), true
+}
+
+// sqlEmbedded report whether the fields of the struct are the columns of the table, and return the prefix of them
+func sqlEmbedded(rs reflect.StructField) (string, bool) {
+	embedded := rs.Anonymous
+	prefix := ""
+	for _, option := range strings.Split(rs.Tag.Get("gorm"), ";") {
+		key, value, _ := strings.Cut(strings.TrimSpace(option), ":")
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "embedded":
+			embedded = true
+		case "embeddedprefix":
+			prefix = strings.TrimSpace(value)
+		}
+	}
+	return prefix, embedded
+}
+
+// sqlValueType report whether the type is a column value, the pointer is followed
+func sqlValueType(rt reflect.Type) bool {
+	switch {
+	case rt.Implements(valuerType) || reflect.PointerTo(rt).Implements(valuerType):
+		return true
+	case rt == timeType:
+		return true
+	case rt.Kind() == reflect.Slice:
+		return rt.Elem().Kind() == reflect.Uint8
+	}
+	return baseTypes[rt.Kind()]
+}
+
+// row return the driver values of the columns, nil if the value is absent, eg: nil ptr
+func (t *sqlTable) row(rv reflect.Value) ([]driver.Value, error) {
+	row := make([]driver.Value, len(t.columns))
+	for i, column := range t.columns {
+		value, ok := rv, true
+		for _, index := range column.path {
+			if value, ok = sqlIndirect(value); !ok {
+				break
+			}
+			value = value.Field(index)
+		}
+		if !ok {
+			continue
+		}
+		if value.Kind() != reflect.Pointer && value.CanAddr() && reflect.PointerTo(value.Type()).Implements(valuerType) {
+			value = value.Addr()
+		}
+		val, err := driver.DefaultParameterConverter.ConvertValue(value.Interface())
+		if err != nil {
+			return nil, fmt.Errorf("field:%s,err:%v", column.name, err)
+		}
+		row[i] = val
+	}
+	return row, nil
+}
+
+func sqlIndirect(rv reflect.Value) (reflect.Value, bool) {
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return rv, false
+		}
+		rv = rv.Elem()
+	}
+	return rv, true
+}
+
+func (t *sqlTable) insert() string {
+	names := make([]string, len(t.columns))
+	for i, column := range t.columns {
+		names[i] = t.quoteIdent(column.name)
+	}
+	parts := strings.Split(t.name, ".") //eg: public.users
+	for i, part := range parts {
+		parts[i] = t.quoteIdent(part)
+	}
+	return "INSERT INTO " + strings.Join(parts, ".") + " (" + strings.Join(names, ", ") + ") VALUES "
+}
+
+func (t *sqlTable) quoteIdent(name string) string {
+	if t.dialect == MySQL {
+		return "`" + backtickEscape.Replace(name) + "`"
+	}
+	return `"` + identEscaper.Replace(name) + `"`
+}
+
+func (t *sqlTable) placeholder(i int) string {
+	if t.dialect == Postgres {
+		return "$" + strconv.Itoa(i)
+	}
+	return "?"
+}
+
+// literal return the sql literal of the driver value
+func (t *sqlTable) literal(value driver.Value) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "NULL", nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return "", fmt.Errorf("not support the decimal:%v", v)
+		}
+		return strconv.FormatFloat(v, 'g', -1, 64), nil
+	case bool:
+		switch {
+		case t.dialect == Postgres && v:
+			return "TRUE", nil
+		case t.dialect == Postgres:
+			return "FALSE", nil
+		case v:
+			return "1", nil
+		}
+		return "0", nil
+	case []byte:
+		if t.dialect == Postgres {
+			return `'\x` + hex.EncodeToString(v) + "'", nil
+		}
+		return "X'" + hex.EncodeToString(v) + "'", nil
+	case string:
+		return t.quoteString(v), nil
+	case time.Time:
+		switch t.dialect {
+		case Postgres:
+			return t.quoteString(v.Format("2006-01-02 15:04:05.999999Z07:00")), nil
+		case MySQL: //DATETIME has no time zone
+			return t.quoteString(v.UTC().Format("2006-01-02 15:04:05.999999")), nil
+		}
+		return t.quoteString(v.Format("2006-01-02 15:04:05.999999999-07:00")), nil
+	}
+	return "", fmt.Errorf("not support the value:%T", value)
+}
+
+func (t *sqlTable) quoteString(s string) string {
+	if t.dialect == MySQL { //the backslash escapes are enabled by default
+		return "'" + mysqlEscaper.Replace(s) + "'"
+	}
+	return "'" + quoteEscaper.Replace(s) + "'"
+}
+
+// snakeCase convert the name like gorm, eg: UserID -> user_id, HTTPServer -> http_server
+func snakeCase(name string) string {
+	var (
+		sb    strings.Builder
+		runes = []rune(name)
+	)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (!unicode.IsUpper(runes[i-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1])) &&
+				runes[i-1] != '_' {
+				sb.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
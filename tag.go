@@ -28,10 +28,12 @@ type TagLevel interface {
 
 type TagLevelMap map[string]TagLevel
 
+var missingTag = &MockTag{isNil: true} //shared by the missing tags, read only
+
 func (tm TagLevelMap) Key(key string) TagLevel {
 	tf, ok := tm[key]
 	if !ok {
-		return &MockTag{isNil: true}
+		return missingTag
 	}
 	return tf
 }
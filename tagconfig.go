@@ -0,0 +1,159 @@
+package gomock
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+type TagConfigMode int
+
+const (
+	TagConfigMerge    TagConfigMode = iota //merge with the struct tag, the config wins on the same key
+	TagConfigOverride                      //replace the struct tag
+)
+
+const tagConfigSeparator = "."
+
+type tagRule struct {
+	tag  string
+	mode TagConfigMode
+}
+
+// RegisterTagConfig register mock tags for types that can not be annotated, the key is the package path, the type
+// and the field name, eg: "github.com/org/app/pb.User.Email": "key=email". the short key, eg: "pb.User.Email",
+// is also accepted but it is shared by the packages of the same name, the full key wins
+func (m *Mock) RegisterTagConfig(config map[string]string, mode TagConfigMode) {
+	m.Lock()
+	defer m.Unlock()
+	if m.tagConfig == nil {
+		m.tagConfig = make(map[string]tagRule, len(config))
+	}
+	for key, tag := range config {
+		m.tagConfig[key] = tagRule{tag: tag, mode: mode}
+	}
+	m.cache.reset()
+}
+
+// mergeTagRule merge the tag into the registered rule of the key, mode is for the new rule
+func (m *Mock) mergeTagRule(key, tag string, mode TagConfigMode) {
+	m.Lock()
+	defer m.Unlock()
+	if m.tagConfig == nil {
+		m.tagConfig = make(map[string]tagRule)
+	}
+	rule, ok := m.tagConfig[key]
+	if ok {
+		rule.tag = m.mergeTag(rule.tag, tag)
+	} else {
+		rule = tagRule{tag: tag, mode: mode}
+	}
+	m.tagConfig[key] = rule
+	m.cache.reset()
+}
+
+// LoadTagConfig load the tag config from a json or yaml file, see RegisterTagConfig
+func (m *Mock) LoadTagConfig(path string, mode TagConfigMode) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	config := make(map[string]string)
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.Unmarshal(data, &config)
+	default:
+		err = yaml.Unmarshal(data, &config)
+	}
+	if err != nil {
+		return err
+	}
+	m.RegisterTagConfig(config, mode)
+	return nil
+}
+
+// TagConfig return the registered tag of the key, see RegisterTagConfig
+func (m *Mock) TagConfig(key string) (string, bool) {
+	m.Lock()
+	defer m.Unlock()
+	rule, ok := m.tagConfig[key]
+	return rule.tag, ok
+}
+
+func (m *Mock) configTag(rt reflect.Type, rs reflect.StructField, tag string) (string, bool) {
+	rule, ok := m.tagConfig[typeKey(rt)+tagConfigSeparator+rs.Name]
+	if !ok {
+		rule, ok = m.tagConfig[rt.String()+tagConfigSeparator+rs.Name] //the short key
+	}
+	if !ok {
+		return tag, tag != ""
+	}
+	if rule.mode == TagConfigOverride || tag == "" {
+		return rule.tag, rule.tag != ""
+	}
+	return m.mergeTag(tag, rule.tag), true
+}
+
+// typeKey return the type in the tag config, eg: github.com/org/app/pb.User
+func typeKey(rt reflect.Type) string {
+	if rt.PkgPath() == "" {
+		return rt.String()
+	}
+	return rt.PkgPath() + tagConfigSeparator + rt.Name()
+}
+
+// mergeTag merge the tags separated by into one by one, the later wins on the same key
+func (m *Mock) mergeTag(tag, other string) string {
+	var (
+		segments      = m.segmentTag(m.splitTag(tag))
+		otherSegments = m.segmentTag(m.splitTag(other))
+	)
+	for i, otherSegment := range otherSegments {
+		if i >= len(segments) {
+			segments = append(segments, otherSegment)
+			continue
+		}
+		for _, otherTag := range otherSegment {
+			segments[i] = m.replaceTag(segments[i], otherTag)
+		}
+	}
+	var tags []string
+	for i, segment := range segments {
+		if i > 0 {
+			tags = append(tags, MockInto+m.tagSeparator+"1")
+		}
+		tags = append(tags, segment...)
+	}
+	return strings.Join(tags, m.separator)
+}
+
+func (m *Mock) segmentTag(tags []string) [][]string {
+	segments := [][]string{nil}
+	for _, tag := range tags {
+		if m.tagKey(tag) == MockInto {
+			segments = append(segments, nil)
+			continue
+		}
+		segments[len(segments)-1] = append(segments[len(segments)-1], tag)
+	}
+	return segments
+}
+
+func (m *Mock) replaceTag(tags []string, tag string) []string {
+	key := m.tagKey(tag)
+	for i := range tags {
+		if m.tagKey(tags[i]) == key {
+			tags[i] = tag
+			return tags
+		}
+	}
+	return append(tags, tag)
+}
+
+func (m *Mock) tagKey(tag string) string {
+	return strings.SplitN(tag, m.tagSeparator, 2)[0]
+}
@@ -0,0 +1,179 @@
+package gomock
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const (
+	validatorSeparator     = ","
+	validatorOrSeparator   = "|"
+	validatorTagSeparator  = "="
+	validatorDive          = "dive"
+	validatorRequired      = "required"
+	validatorUrlPattern    = "https://[a-z]{3,10}\\.(com|net|org)(/[a-z0-9]{1,8}){0,3}"
+	validatorUuidPattern   = "[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}"
+	validatorDefaultStrLen = "1"
+	validatorDefaultMaxDec = "1000" //decimal without upper limit is always 0
+	validatorDecimalRange  = 1000   //the upper limit of the decimal with only the lower limit is lower+range
+)
+
+// TagSource return the mock tag of the struct field, ok is false if the field has no mock rules.
+// the mock tag always takes precedence, the sources are consulted in the order of registration.
+type TagSource func(rs reflect.StructField) (tag string, ok bool)
+
+// RegisterTagSource register a tag source for the fields without mock tag
+func (m *Mock) RegisterTagSource(ts TagSource) {
+	if ts == nil {
+		return
+	}
+	m.Lock()
+	defer m.Unlock()
+	m.tagSources = append(m.tagSources, ts)
+	m.cache.reset()
+}
+
+// fieldTag return the mock tag of the field in the struct rt, the tag config is applied at last
+func (m *Mock) fieldTag(rt reflect.Type, rs reflect.StructField) (string, bool) {
+	tag := rs.Tag.Get(m.tag)
+	for i := 0; tag == "" && i < len(m.tagSources); i++ {
+		if source, ok := m.tagSources[i](rs); ok {
+			tag = source
+			break
+		}
+	}
+	return m.configTag(rt, rs, tag)
+}
+
+// ValidatorTagSource translate the go-playground/validator rules of the tag name(eg: validate, binding) into mock tag,
+// support min, max, len, eq, gt, gte, lt, lte, oneof, email, url, uuid, required and dive, the others are ignored,
+// eg: omitempty, the mocked value follows the other rules and is never empty
+func ValidatorTagSource(name string) TagSource {
+	return func(rs reflect.StructField) (string, bool) {
+		tag := rs.Tag.Get(name)
+		if tag == "" || tag == "-" {
+			return "", false
+		}
+		tags := translateValidator(rs.Type, strings.Split(tag, validatorSeparator))
+		if len(tags) == 0 { //not supported kind, eg: bool, map
+			return "", false
+		}
+		return strings.Join(tags, defaultSeparator), true
+	}
+}
+
+func translateValidator(rt reflect.Type, rules []string) []string {
+	if rt.Kind() == reflect.Pointer {
+		rt = rt.Elem()
+	}
+	var (
+		tags     []string
+		key      string
+		length   bool //limit the length or the value
+		required bool
+	)
+	for i, rule := range rules {
+		rule = strings.SplitN(rule, validatorOrSeparator, 2)[0] //only the first of or
+		values := strings.SplitN(rule, validatorTagSeparator, 2)
+		name, param := values[0], ""
+		if len(values) > 1 {
+			param = values[1]
+		}
+		switch name {
+		case validatorDive:
+			if rt.Kind() != reflect.Slice {
+				continue
+			}
+			tags = append(validatorKey(rt, key, length, required, tags), MockInto+mockTagSeparator+"1")
+			return append(tags, translateValidator(rt.Elem(), rules[i+1:])...)
+		case validatorRequired:
+			required = true
+		case "min", "gte":
+			tags, length = append(tags, MockGte+mockTagSeparator+param), true
+		case "max", "lte":
+			tags, length = append(tags, MockLte+mockTagSeparator+param), true
+		case "gt", "lt":
+			tags, length = append(tags, name+mockTagSeparator+param), true
+		case "len":
+			if rt.Kind() == reflect.String {
+				tags = append(tags, MockGte+mockTagSeparator+param, MockLte+mockTagSeparator+param)
+			} else {
+				tags = append(tags, MockEqual+mockTagSeparator+param)
+			}
+			length = true
+		case "eq":
+			tags, length = append(tags, MockEqual+mockTagSeparator+param), true
+		case "oneof":
+			tags, length = append(tags, MockOptions+mockTagSeparator+param), true
+		case "email":
+			key, length = makeEmail, true
+		case "url", "http_url", "uri":
+			tags, length = append(tags, MockRegExp+mockTagSeparator+validatorUrlPattern), true
+		case "uuid", "uuid4", "uuid_rfc4122", "uuid4_rfc4122":
+			tags, length = append(tags, MockRegExp+mockTagSeparator+validatorUuidPattern), true
+		}
+	}
+	return validatorKey(rt, key, length, required, tags)
+}
+
+// validatorKey prepend the mock function by the kind, required without any limit means not zero
+func validatorKey(rt reflect.Type, key string, length, required bool, tags []string) []string {
+	switch rt.Kind() {
+	case reflect.String:
+		if key == "" {
+			key = makeString
+		}
+		if required && !length {
+			tags = append(tags, MockGte+mockTagSeparator+validatorDefaultStrLen)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		key = makeInteger
+		if required && !length {
+			tags = append(tags, MockGte+mockTagSeparator+"1")
+		}
+	case reflect.Float32, reflect.Float64:
+		key = makeDecimal
+		if required && !length {
+			tags = append(tags, MockGt+mockTagSeparator+"0", MockLte+mockTagSeparator+validatorDefaultMaxDec)
+		} else {
+			tags = decimalUpper(tags)
+		}
+	case reflect.Slice:
+		if required && !length {
+			tags = append(tags, MockGte+mockTagSeparator+"1")
+		}
+		return tags
+	case reflect.Struct:
+		return append(tags, MockInto+mockTagSeparator+"1")
+	default:
+		return nil
+	}
+	return append([]string{MockKey + mockTagSeparator + key}, tags...)
+}
+
+// decimalUpper append the upper limit to the decimal with only the lower limit, eg: min=5 -> gte=5,lte=1005
+func decimalUpper(tags []string) []string {
+	var (
+		lower    float64
+		hasLower bool
+	)
+	for _, tag := range tags {
+		key, value, _ := strings.Cut(tag, mockTagSeparator)
+		switch key {
+		case MockLte, MockLt, MockEqual, MockOptions:
+			return tags
+		case MockGte, MockGt:
+			val, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return tags
+			}
+			lower, hasLower = val, true
+		}
+	}
+	if !hasLower {
+		return tags
+	}
+	return append(tags, MockLte+mockTagSeparator+strconv.FormatFloat(lower+validatorDecimalRange, 'f', -1, 64))
+}
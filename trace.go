@@ -0,0 +1,162 @@
+package gomock
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+)
+
+const (
+	BranchEqual    = "eq"
+	BranchOptions  = "options"
+	BranchRegExp   = "reg"
+	BranchRange    = "range"
+	BranchOverride = "override"
+)
+
+type traceKey struct{}
+type traceEntryKey struct{}
+
+// TraceEntry records how the value of a field is produced
+type TraceEntry struct {
+	Path   string            `json:"path"`   //the field path, slice elements are addressed by index
+	Alias  string            `json:"alias"`  //the field alias
+	Key    string            `json:"key"`    //the mock function, override for WithOverride
+	Tags   map[string]string `json:"tags"`   //the mock tags of the field
+	Branch string            `json:"branch"` //the branch of the mock function, eg: eq, options, reg, range
+	Value  any               `json:"value"`  //the produced value, the length for slice
+}
+
+// Trace collects the entries of the calls given by WithTrace, it is safe for concurrent use
+type Trace struct {
+	lock    sync.Mutex
+	entries []*TraceEntry
+}
+
+func NewTrace() *Trace {
+	return &Trace{}
+}
+
+// WithTrace record how every value is produced in this call into tr
+func WithTrace(ctx context.Context, tr *Trace) context.Context {
+	if tr == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, traceKey{}, tr)
+}
+
+// TraceBranch record the branch taken by the mock function, the custom mock function may call it
+func TraceBranch(ctx context.Context, branch string) {
+	if entry, ok := ctx.Value(traceEntryKey{}).(*TraceEntry); ok {
+		entry.Branch = branch
+	}
+}
+
+func hasTrace(ctx context.Context) bool {
+	_, ok := ctx.Value(traceKey{}).(*Trace)
+	return ok
+}
+
+// startTrace add an entry of the field to the trace of the call
+func startTrace(ctx context.Context, fl FieldLevel) (context.Context, *TraceEntry) {
+	tr, ok := ctx.Value(traceKey{}).(*Trace)
+	if !ok {
+		return ctx, nil
+	}
+	entry := &TraceEntry{
+		Path:  fieldPath(ctx, fl),
+		Alias: fl.GetAlias(),
+		Key:   traceMockKey(ctx, fl),
+		Tags:  make(map[string]string, len(fl.GetTags())),
+	}
+	for key, tl := range fl.GetTags() {
+		entry.Tags[key] = tl.GetStr()
+	}
+	if entry.Key == BranchOverride {
+		entry.Branch = BranchOverride
+	}
+	tr.lock.Lock()
+	tr.entries = append(tr.entries, entry)
+	tr.lock.Unlock()
+	return context.WithValue(ctx, traceEntryKey{}, entry), entry
+}
+
+func traceMockKey(ctx context.Context, fl FieldLevel) string {
+	switch {
+	case lookupOverride(ctx, fl) != nil:
+		return BranchOverride
+	case fl.GetTags().Key(MockKey).Exists():
+		return fl.GetTags().Key(MockKey).GetKey()
+	case fl.GetKind() == reflect.Slice:
+		return makeSlice
+	}
+	return makeStruct
+}
+
+// finish record the value of the field
+func (entry *TraceEntry) finish(val reflect.Value) {
+	if entry == nil {
+		return
+	}
+	if val.Kind() == reflect.Pointer {
+		if val.IsNil() {
+			return
+		}
+		val = val.Elem()
+	}
+	switch val.Kind() {
+	case reflect.Struct:
+	case reflect.Slice:
+		entry.Value = val.Len()
+	default:
+		entry.Value = val.Interface()
+	}
+}
+
+// Entries return the recorded entries in the order of mocking
+func (tr *Trace) Entries() []*TraceEntry {
+	tr.lock.Lock()
+	defer tr.lock.Unlock()
+	return append([]*TraceEntry(nil), tr.entries...)
+}
+
+// Reset remove all the entries
+func (tr *Trace) Reset() {
+	tr.lock.Lock()
+	defer tr.lock.Unlock()
+	tr.entries = nil
+}
+
+func (tr *Trace) MarshalJSON() ([]byte, error) {
+	return json.Marshal(tr.Entries())
+}
+
+// WriteTable write the entries as a table
+func (tr *Trace) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	if _, err := fmt.Fprintln(tw, "PATH\tKEY\tBRANCH\tVALUE\tTAGS"); err != nil {
+		return err
+	}
+	for _, entry := range tr.Entries() {
+		tags := mapToSlice(entry.Tags, func(key, value string) string {
+			return key + mockTagSeparator + value
+		})
+		sort.Strings(tags)
+		value := ""
+		if entry.Value != nil {
+			value = fmt.Sprint(entry.Value)
+		}
+		_, err := fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", entry.Path, entry.Key, entry.Branch, value,
+			strings.Join(tags, defaultSeparator))
+		if err != nil {
+			return err
+		}
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,298 @@
+package gomock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// regexp cache for the reg tag, map[string]*regexp.Regexp
+var regexpCache = &sync.Map{}
+
+type FieldError struct {
+	Path  string //the field path, slice elements are addressed by index, eg: Hobbies.1.Name
+	Tag   string //the violated mock tag
+	Param string //the value of the violated mock tag
+	Value any    //the current value of the field
+}
+
+func (fe *FieldError) Error() string {
+	return fmt.Sprintf("field:%s,value:%v,violate:%s=%s", fe.Path, fe.Value, fe.Tag, fe.Param)
+}
+
+// ValidationErrors is returned by Validate, one error per invalid field
+type ValidationErrors []*FieldError
+
+func (ve ValidationErrors) Error() string {
+	values := make([]string, 0, len(ve))
+	for _, fe := range ve {
+		values = append(values, fe.Error())
+	}
+	return strings.Join(values, ";")
+}
+
+// walkFunc is called for every parsed field of a value, val holds the field value as declared (maybe a ptr)
+type walkFunc func(val reflect.Value, fl FieldLevel, path string) error
+
+// Validate check the current values of the struct against its mock tags
+func (m *Mock) Validate(s any) error {
+	return m.ValidateCtx(context.Background(), s)
+}
+func (m *Mock) ValidateCtx(ctx context.Context, s any) error {
+	val := reflect.ValueOf(s)
+	if val.Kind() != reflect.Pointer || val.Elem().Kind() != reflect.Struct {
+		return errors.New("not a initialize struct ptr")
+	}
+	fl, err := m.genCache(ctx, val)
+	if err != nil {
+		return err
+	}
+	var ve ValidationErrors
+	err = m.walkValue(val, fl, "", func(val reflect.Value, fl FieldLevel, path string) error {
+		if fe := validateField(val, fl, path); fe != nil {
+			ve = append(ve, fe)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if len(ve) > 0 {
+		return ve
+	}
+	return nil
+}
+
+// ValidateTag check the value of the base type or slice against the mock tag as a field, eg: ValidateTag(reflect.ValueOf(5), "gte=1,lte=3").
+// it is used for the values out of struct like MockTag, the returned FieldError has an empty path
+func (m *Mock) ValidateTag(val reflect.Value, tag string) error {
+	fl, err := m.leafField(val.Type(), m.tagPairs(tag))
+	if err != nil {
+		return err
+	}
+	if fe := validateField(val, fl, ""); fe != nil {
+		return fe
+	}
+	return nil
+}
+
+// walkValue visit the value along the parsed field tree, depth first and in field order
+func (m *Mock) walkValue(val reflect.Value, fl FieldLevel, path string, fn walkFunc) error {
+	if err := fn(val, fl, path); err != nil {
+		return err
+	}
+	if fl.IsPtr() {
+		if val.IsNil() {
+			return nil
+		}
+		val = val.Elem()
+	}
+	var err error
+	switch fl.GetKind() {
+	case reflect.Struct:
+		for _, field := range fl.GetChildren() {
+			err = m.walkValue(val.Field(field.GetIndex()), field, joinPath(path, field.GetName()), fn)
+			if err != nil {
+				return err
+			}
+		}
+	case reflect.Slice:
+		if len(fl.GetChildren()) == 0 { //not into
+			return nil
+		}
+		for i := 0; i < val.Len(); i++ {
+			err = m.walkValue(val.Index(i), fl.GetChildren()[0], joinPath(path, strconv.Itoa(i)), fn)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	if name == "" {
+		return path
+	}
+	return path + "." + name
+}
+
+// validateField check the value follow the same priority as the generators: eq, options, reg, range
+func validateField(val reflect.Value, fl FieldLevel, path string) *FieldError {
+	tm := fl.GetTags()
+	if len(tm) == 0 || tm.Key(MockSkip).Exists() {
+		return nil
+	}
+	if fl.IsPtr() {
+		if val.IsNil() {
+			return nil
+		}
+		val = val.Elem()
+	}
+	var tag string
+	switch fl.GetKind() {
+	case reflect.String:
+		tag = validateString(tm, val.String())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		tag = validateInteger(tm, val.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		tag = validateUnsigned(tm, val.Uint())
+	case reflect.Float32:
+		tag = validateDecimal(tm, val.Float(), 32)
+	case reflect.Float64:
+		tag = validateDecimal(tm, val.Float(), 64)
+	case reflect.Slice:
+		tag = validateSlice(tm, val.Len())
+	}
+	if tag == "" {
+		return nil
+	}
+	return &FieldError{
+		Path:  path,
+		Tag:   tag,
+		Param: tm.Key(tag).GetStr(),
+		Value: val.Interface(),
+	}
+}
+
+func validateString(tm TagLevelMap, value string) string {
+	if eqVal := tm.Key(MockEqual).GetStr(); eqVal != "" {
+		return violateIf(value != eqVal, MockEqual)
+	}
+	if tm.Key(MockOptions).Exists() {
+		return violateIf(!contains(tm.Key(MockOptions).GetStrSet(), value), MockOptions)
+	}
+	if tm.Key(MockRegExp).Exists() {
+		return violateIf(!matchRegExp(tm.Key(MockRegExp).GetStr(), value), MockRegExp)
+	}
+	return violateIntRange(tm, int64(utf8.RuneCountInString(value)))
+}
+
+func validateInteger(tm TagLevelMap, value int64) string {
+	if tm.Key(MockEqual).Exists() {
+		return violateIf(value != tm.Key(MockEqual).GetInt64(), MockEqual)
+	}
+	if tm.Key(MockOptions).Exists() {
+		return violateIf(!contains(tm.Key(MockOptions).GetInt64Set(), value), MockOptions)
+	}
+	if tm.Key(MockRegExp).Exists() { //the text is lost after parsing, eg: leading zeros
+		return ""
+	}
+	return violateIntRange(tm, value)
+}
+
+// validateUnsigned compare in the uint64 domain, the value above MaxInt64 is greater than all the tag values
+func validateUnsigned(tm TagLevelMap, value uint64) string {
+	if value <= math.MaxInt64 {
+		return validateInteger(tm, int64(value))
+	}
+	switch {
+	case tm.Key(MockEqual).Exists():
+		return MockEqual
+	case tm.Key(MockOptions).Exists():
+		return MockOptions
+	case tm.Key(MockRegExp).Exists():
+		return ""
+	case tm.Key(MockLt).Exists():
+		return MockLt
+	case tm.Key(MockLte).Exists():
+		return MockLte
+	}
+	return ""
+}
+
+// the tag values are rounded to bitSize, float32 fields can not hold them exactly
+func validateDecimal(tm TagLevelMap, value float64, bitSize int) string {
+	round := func(v float64) float64 {
+		if bitSize == 32 {
+			return float64(float32(v))
+		}
+		return v
+	}
+	if tm.Key(MockEqual).Exists() {
+		return violateIf(value != round(tm.Key(MockEqual).GetFloat64()), MockEqual)
+	}
+	if tm.Key(MockOptions).Exists() {
+		options := tm.Key(MockOptions).GetFloat64Set()
+		rounded := make([]float64, 0, len(options))
+		for _, option := range options {
+			rounded = append(rounded, round(option))
+		}
+		return violateIf(!contains(rounded, value), MockOptions)
+	}
+	if tm.Key(MockRegExp).Exists() { //the text is lost after parsing, eg: trailing zeros
+		return ""
+	}
+	switch {
+	case tm.Key(MockGt).Exists() && value <= round(tm.Key(MockGt).GetFloat64()):
+		return MockGt
+	case tm.Key(MockGte).Exists() && value < round(tm.Key(MockGte).GetFloat64()):
+		return MockGte
+	case tm.Key(MockLt).Exists() && value >= round(tm.Key(MockLt).GetFloat64()):
+		return MockLt
+	case tm.Key(MockLte).Exists() && value > round(tm.Key(MockLte).GetFloat64()):
+		return MockLte
+	}
+	return ""
+}
+
+// for slice, eq and range limit the length
+func validateSlice(tm TagLevelMap, length int) string {
+	if eq := tm.Key(MockEqual).GetInt(); eq > 0 {
+		return violateIf(length != eq, MockEqual)
+	}
+	return violateIntRange(tm, int64(length))
+}
+
+func violateIntRange(tm TagLevelMap, value int64) string {
+	switch {
+	case tm.Key(MockGt).Exists() && value <= tm.Key(MockGt).GetInt64():
+		return MockGt
+	case tm.Key(MockGte).Exists() && value < tm.Key(MockGte).GetInt64():
+		return MockGte
+	case tm.Key(MockLt).Exists() && value >= tm.Key(MockLt).GetInt64():
+		return MockLt
+	case tm.Key(MockLte).Exists() && value > tm.Key(MockLte).GetInt64():
+		return MockLte
+	}
+	return ""
+}
+
+func violateIf(violated bool, tag string) string {
+	if violated {
+		return tag
+	}
+	return ""
+}
+
+func contains[T string | int64 | float64](values []T, value T) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// the generated value matches the whole pattern, so does the validation
+func matchRegExp(pattern, value string) bool {
+	reg, ok := regexpCache.Load(pattern)
+	if !ok {
+		compiled, err := regexp.Compile("^(?:" + pattern + ")$")
+		if err != nil {
+			return false
+		}
+		reg, _ = regexpCache.LoadOrStore(pattern, compiled)
+	}
+	return reg.(*regexp.Regexp).MatchString(value)
+}